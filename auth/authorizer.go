@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+// TokenParameter is the query string parameter clients pass their token in when they connect
+const TokenParameter = "token"
+
+// displayNameKey is the key of the principal's display name in the authorizer's context
+const displayNameKey = "displayName"
+
+// errUnauthorized is the error API Gateway expects from an authorizer to answer the connection with a 401
+var errUnauthorized = errors.New("Unauthorized")
+
+// Authorize is the $connect route's request authorizer. It allows the connection if its token is valid, with the
+// token's subject as the principal, which API Gateway passes on to every route in RequestContext.Authorizer along with
+// the token's name, if it has one
+func (v *Verifier) Authorize(_ context.Context, request events.APIGatewayCustomAuthorizerRequestTypeRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	claims, err := v.Verify(request.QueryStringParameters[TokenParameter])
+	if err != nil {
+		log.Info(err)
+		return events.APIGatewayCustomAuthorizerResponse{}, errUnauthorized
+	}
+
+	var authorizerContext map[string]interface{}
+	if claims.Name != "" {
+		authorizerContext = map[string]interface{}{displayNameKey: claims.Name}
+	}
+
+	return events.APIGatewayCustomAuthorizerResponse{
+		PrincipalID: claims.Subject,
+		PolicyDocument: events.APIGatewayCustomAuthorizerPolicy{
+			Version: "2012-10-17",
+			Statement: []events.IAMPolicyStatement{
+				{
+					Action:   []string{"execute-api:Invoke"},
+					Effect:   "Allow",
+					Resource: []string{request.MethodArn},
+				},
+			},
+		},
+		Context: authorizerContext,
+	}, nil
+}
+
+// AuthorizerContext is what API Gateway passes to routes in RequestContext.Authorizer for a principal, given the
+// context Authorize allowed it with
+func AuthorizerContext(principalId string, authorizerContext map[string]interface{}) map[string]interface{} {
+	context := map[string]interface{}{"principalId": principalId}
+	for key, value := range authorizerContext {
+		context[key] = value
+	}
+	return context
+}
+
+// PrincipalId is the principal the $connect authorizer allowed the event's connection for, or empty if the
+// connection wasn't authorized
+func PrincipalId(websocketEvent events.APIGatewayWebsocketProxyRequest) string {
+	authorizer, ok := websocketEvent.RequestContext.Authorizer.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	principalId, _ := authorizer["principalId"].(string)
+	return principalId
+}
+
+// DisplayName is the name from the token the event's connection was authorized with, or empty if the token had no
+// name
+func DisplayName(websocketEvent events.APIGatewayWebsocketProxyRequest) string {
+	authorizer, ok := websocketEvent.RequestContext.Authorizer.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	displayName, _ := authorizer[displayNameKey].(string)
+	return displayName
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const testMethodArn = "arn:aws:execute-api:eu-west-2:123456789012:api-id/stage/$connect"
+
+func newAuthorizerRequest(queryStringParameters map[string]string) events.APIGatewayCustomAuthorizerRequestTypeRequest {
+	return events.APIGatewayCustomAuthorizerRequestTypeRequest{
+		Type:                  "REQUEST",
+		MethodArn:             testMethodArn,
+		QueryStringParameters: queryStringParameters,
+	}
+}
+
+func TestVerifier_Authorize(t *testing.T) {
+	verifier := NewVerifier([]crypto.PublicKey{ecdsaKey.Public()}, "", "")
+
+	response, err := verifier.Authorize(context.Background(), newAuthorizerRequest(map[string]string{TokenParameter: sign(t, validClaims(), ecdsaKey)}))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "player", response.PrincipalID)
+	assert.Equal(t, []events.IAMPolicyStatement{
+		{
+			Action:   []string{"execute-api:Invoke"},
+			Effect:   "Allow",
+			Resource: []string{testMethodArn},
+		},
+	}, response.PolicyDocument.Statement)
+	assert.Nil(t, response.Context)
+}
+
+func TestVerifier_Authorize_Name(t *testing.T) {
+	verifier := NewVerifier([]crypto.PublicKey{ecdsaKey.Public()}, "", "")
+	claims := validClaims()
+	claims.Name = "Player One"
+
+	response, err := verifier.Authorize(context.Background(), newAuthorizerRequest(map[string]string{TokenParameter: sign(t, claims, ecdsaKey)}))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, map[string]interface{}{"displayName": "Player One"}, response.Context)
+}
+
+func TestVerifier_Authorize_Unauthorized(t *testing.T) {
+	verifier := NewVerifier([]crypto.PublicKey{ecdsaKey.Public()}, "", "")
+
+	for _, queryStringParameters := range []map[string]string{
+		{},
+		{TokenParameter: "a-secret-token-kept-by-the-client"},
+		{TokenParameter: sign(t, validClaims(), rsaKey)},
+	} {
+		response, err := verifier.Authorize(context.Background(), newAuthorizerRequest(queryStringParameters))
+
+		assert.Equal(t, "Unauthorized", err.Error())
+		assert.Equal(t, events.APIGatewayCustomAuthorizerResponse{}, response)
+	}
+}
+
+func TestPrincipalId(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorizer interface{}
+		expected   string
+	}{
+		{
+			name:       "authorized",
+			authorizer: AuthorizerContext("player", nil),
+			expected:   "player",
+		},
+		{
+			name:       "decoded from JSON",
+			authorizer: map[string]interface{}{"principalId": "player", "integrationLatency": float64(12)},
+			expected:   "player",
+		},
+		{
+			name:     "not authorized",
+			expected: "",
+		},
+		{
+			name:       "no principal",
+			authorizer: map[string]interface{}{},
+			expected:   "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			websocketEvent := events.APIGatewayWebsocketProxyRequest{
+				RequestContext: events.APIGatewayWebsocketProxyRequestContext{Authorizer: test.authorizer},
+			}
+
+			assert.Equal(t, test.expected, PrincipalId(websocketEvent))
+		})
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorizer interface{}
+		expected   string
+	}{
+		{
+			name:       "named",
+			authorizer: AuthorizerContext("player", map[string]interface{}{"displayName": "Player One"}),
+			expected:   "Player One",
+		},
+		{
+			name:       "not named",
+			authorizer: AuthorizerContext("player", nil),
+			expected:   "",
+		},
+		{
+			name:     "not authorized",
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			websocketEvent := events.APIGatewayWebsocketProxyRequest{
+				RequestContext: events.APIGatewayWebsocketProxyRequestContext{Authorizer: test.authorizer},
+			}
+
+			assert.Equal(t, test.expected, DisplayName(websocketEvent))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// ParsePublicKeys reads every public key from PEM data, which can hold any number of PUBLIC KEY or RSA PUBLIC KEY
+// blocks
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var key crypto.PublicKey
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		default:
+			err = fmt.Errorf("%s is not a public key", block.Type)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public keys found")
+	}
+	return keys, nil
+}
+
+// ParsePrivateKey reads the first private key from PEM data, in PKCS #8, PKCS #1 or SEC 1 form
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no private key found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%T keys cannot sign tokens", key)
+		}
+		return signer, nil
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s is not a private key", block.Type)
+	}
+}
+
+// NewVerifierFromEnvironment trusts the PEM encoded keys in JWT_PUBLIC_KEYS, and checks tokens against JWT_ISSUER and
+// JWT_AUDIENCE if they are set
+func NewVerifierFromEnvironment() (*Verifier, error) {
+	keys, err := ParsePublicKeys([]byte(os.Getenv("JWT_PUBLIC_KEYS")))
+	if err != nil {
+		return nil, fmt.Errorf("JWT_PUBLIC_KEYS is not valid - %w", err)
+	}
+
+	return NewVerifier(keys, os.Getenv("JWT_ISSUER"), os.Getenv("JWT_AUDIENCE")), nil
+}
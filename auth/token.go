@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/golang-jwt/jwt/v5"
+	"time"
+)
+
+// The signing algorithms tokens can use, as named in a token's alg header
+const (
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+// clockSkew is how far the issuer's clock can be ahead of or behind ours before a token's times are rejected
+const clockSkew = time.Minute
+
+var now = time.Now
+
+// reservedSubjects are the player IDs stored for sides of a game that aren't played by anyone who can connect, so a
+// token for one of them would let its holder play those sides
+var reservedSubjects = map[string]bool{
+	engine.PlayerId:         true,
+	game.ImportedOpponentId: true,
+}
+
+type InvalidTokenError struct {
+	reason string
+}
+
+func (e *InvalidTokenError) Error() string {
+	return fmt.Sprintf("token is not valid - %s", e.reason)
+}
+
+func (e *InvalidTokenError) Code() string {
+	return "INVALID_TOKEN"
+}
+
+func (e *InvalidTokenError) Is(target error) bool {
+	return target == errs.ErrUnauthorized
+}
+
+// Audience is the aud claim, which can be written as either a single string or a list of them
+type Audience []string
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+// Claims are the registered JWT claims the game checks. The subject is the player the token was issued to
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	// Name is the name the player is shown to other players as
+	Name string `json:"name,omitempty"`
+}
+
+// The methods below let the claims be validated by jwt, which reads the times as seconds since the Unix epoch
+
+func (c Claims) GetExpirationTime() (*jwt.NumericDate, error) {
+	return numericDate(c.ExpiresAt), nil
+}
+
+func (c Claims) GetNotBefore() (*jwt.NumericDate, error) {
+	return numericDate(c.NotBefore), nil
+}
+
+func (c Claims) GetIssuedAt() (*jwt.NumericDate, error) {
+	return numericDate(c.IssuedAt), nil
+}
+
+func (c Claims) GetIssuer() (string, error) {
+	return c.Issuer, nil
+}
+
+func (c Claims) GetSubject() (string, error) {
+	return c.Subject, nil
+}
+
+func (c Claims) GetAudience() (jwt.ClaimStrings, error) {
+	return jwt.ClaimStrings(c.Audience), nil
+}
+
+func numericDate(seconds int64) *jwt.NumericDate {
+	if seconds == 0 {
+		return nil
+	}
+	return jwt.NewNumericDate(time.Unix(seconds, 0))
+}
+
+// Verifier checks tokens against the public keys of the issuer's signing keys. More than one key can be trusted at a
+// time so that keys can be rotated without cutting off tokens signed by the old one
+type Verifier struct {
+	keys     []crypto.PublicKey
+	issuer   string
+	audience string
+}
+
+// NewVerifier trusts tokens signed by any of keys. If issuer or audience are set, tokens must also have been issued
+// by issuer and for audience
+func NewVerifier(keys []crypto.PublicKey, issuer string, audience string) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+// Verify checks the token was signed by one of the verifier's keys and is valid now, and returns its claims
+func (v *Verifier) Verify(token string) (Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{RS256, ES256}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(now),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	var claims Claims
+	if _, err := jwt.ParseWithClaims(token, &claims, v.keysFor, options...); err != nil {
+		return Claims{}, &InvalidTokenError{reason: err.Error()}
+	}
+	if claims.Subject == "" {
+		return Claims{}, &InvalidTokenError{reason: "it has no subject"}
+	}
+	if reservedSubjects[claims.Subject] {
+		return Claims{}, &InvalidTokenError{reason: fmt.Sprintf("its subject %s is reserved", claims.Subject)}
+	}
+	return claims, nil
+}
+
+// keysFor only offers the keys whose type goes with the token's algorithm, so that a token can't choose how its
+// signature is checked
+func (v *Verifier) keysFor(token *jwt.Token) (interface{}, error) {
+	keys := jwt.VerificationKeySet{}
+	for _, key := range v.keys {
+		switch key := key.(type) {
+		case *rsa.PublicKey:
+			if token.Method == jwt.SigningMethodRS256 {
+				keys.Keys = append(keys.Keys, key)
+			}
+		case *ecdsa.PublicKey:
+			if token.Method == jwt.SigningMethodES256 && key.Curve == elliptic.P256() {
+				keys.Keys = append(keys.Keys, key)
+			}
+		}
+	}
+
+	if len(keys.Keys) == 0 {
+		return nil, fmt.Errorf("there are no trusted %s keys", token.Method.Alg())
+	}
+	return keys, nil
+}
+
+// Sign issues a token with claims, signed by key with the algorithm for its type: RS256 for RSA keys and ES256 for
+// P-256 ECDSA keys
+func Sign(claims Claims, key crypto.Signer) (string, error) {
+	var method jwt.SigningMethod
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case *ecdsa.PrivateKey:
+		if key.Curve != elliptic.P256() {
+			return "", fmt.Errorf("ES256 needs a P-256 key, not %s", key.Curve.Params().Name)
+		}
+		method = jwt.SigningMethodES256
+	default:
+		return "", fmt.Errorf("%T keys cannot sign tokens", key)
+	}
+
+	return jwt.NewWithClaims(method, claims).SignedString(key)
+}
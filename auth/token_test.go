@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+var (
+	ecdsaKey = newECDSAKey()
+	rsaKey   = newRSAKey()
+)
+
+func init() {
+	now = func() time.Time {
+		return testTime
+	}
+}
+
+func newECDSAKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func newRSAKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func validClaims() Claims {
+	return Claims{
+		Subject:   "player",
+		Issuer:    "https://issuer.example.com",
+		Audience:  Audience{"tic-tac-toe"},
+		ExpiresAt: testTime.Add(time.Hour).Unix(),
+		IssuedAt:  testTime.Unix(),
+	}
+}
+
+func sign(t *testing.T, claims Claims, key crypto.Signer) string {
+	token, err := Sign(claims, key)
+	assert.Equal(t, nil, err)
+	return token
+}
+
+// hmacSigned signs claims with the ECDSA key's public key as an HS256 secret, as anyone who has the public key could
+func hmacSigned(t *testing.T, claims Claims) string {
+	secret, _ := x509.MarshalPKIXPublicKey(ecdsaKey.Public())
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	assert.Equal(t, nil, err)
+	return token
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	verifier := NewVerifier([]crypto.PublicKey{ecdsaKey.Public(), rsaKey.Public()}, "https://issuer.example.com", "tic-tac-toe")
+
+	for _, key := range []crypto.Signer{ecdsaKey, rsaKey} {
+		claims, err := verifier.Verify(sign(t, validClaims(), key))
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, validClaims(), claims)
+	}
+}
+
+func TestVerifier_Verify_Invalid(t *testing.T) {
+	verifier := NewVerifier([]crypto.PublicKey{ecdsaKey.Public()}, "https://issuer.example.com", "tic-tac-toe")
+
+	withClaims := func(change func(claims *Claims)) string {
+		claims := validClaims()
+		change(&claims)
+		return sign(t, claims, ecdsaKey)
+	}
+	valid := sign(t, validClaims(), ecdsaKey)
+	parts := strings.Split(valid, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "empty",
+			token: "",
+		},
+		{
+			name:  "not a JWT",
+			token: "a-secret-token-kept-by-the-client",
+		},
+		{
+			name:  "untrusted ECDSA key",
+			token: sign(t, validClaims(), newECDSAKey()),
+		},
+		{
+			name:  "untrusted RSA key",
+			token: sign(t, validClaims(), rsaKey),
+		},
+		{
+			name:  "unsigned",
+			token: base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + ".",
+		},
+		{
+			name:  "signed with the public key as an HMAC secret",
+			token: hmacSigned(t, validClaims()),
+		},
+		{
+			name:  "claims changed after signing",
+			token: parts[0] + "." + strings.Split(withClaims(func(claims *Claims) { claims.Subject = "someone else" }), ".")[1] + "." + parts[2],
+		},
+		{
+			name:  "malformed signature",
+			token: parts[0] + "." + parts[1] + ".not base64!",
+		},
+		{
+			name:  "no subject",
+			token: withClaims(func(claims *Claims) { claims.Subject = "" }),
+		},
+		{
+			name:  "subject is the computer",
+			token: withClaims(func(claims *Claims) { claims.Subject = engine.PlayerId }),
+		},
+		{
+			name:  "subject is an imported opponent",
+			token: withClaims(func(claims *Claims) { claims.Subject = game.ImportedOpponentId }),
+		},
+		{
+			name:  "no expiry",
+			token: withClaims(func(claims *Claims) { claims.ExpiresAt = 0 }),
+		},
+		{
+			name:  "expired",
+			token: withClaims(func(claims *Claims) { claims.ExpiresAt = testTime.Add(-2 * clockSkew).Unix() }),
+		},
+		{
+			name:  "not yet valid",
+			token: withClaims(func(claims *Claims) { claims.NotBefore = testTime.Add(2 * clockSkew).Unix() }),
+		},
+		{
+			name:  "wrong issuer",
+			token: withClaims(func(claims *Claims) { claims.Issuer = "https://elsewhere.example.com" }),
+		},
+		{
+			name:  "wrong audience",
+			token: withClaims(func(claims *Claims) { claims.Audience = Audience{"another-app"} }),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims, err := verifier.Verify(test.token)
+
+			assert.Equal(t, Claims{}, claims)
+			assert.IsType(t, &InvalidTokenError{}, err)
+			assert.Equal(t, "INVALID_TOKEN", err.(*InvalidTokenError).Code())
+			assert.True(t, errors.Is(err, errs.ErrUnauthorized))
+		})
+	}
+}
+
+func TestVerifier_Verify_WithinClockSkew(t *testing.T) {
+	verifier := NewVerifier([]crypto.PublicKey{ecdsaKey.Public()}, "", "")
+	claims := validClaims()
+	claims.ExpiresAt = testTime.Add(-clockSkew / 2).Unix()
+	claims.NotBefore = testTime.Add(clockSkew / 2).Unix()
+
+	_, err := verifier.Verify(sign(t, claims, ecdsaKey))
+
+	assert.Equal(t, nil, err)
+}
+
+func TestAudience_UnmarshalJSON(t *testing.T) {
+	var claims Claims
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"aud": "tic-tac-toe"}`), &claims))
+	assert.Equal(t, Audience{"tic-tac-toe"}, claims.Audience)
+
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"aud": ["another-app", "tic-tac-toe"]}`), &claims))
+	assert.Equal(t, Audience{"another-app", "tic-tac-toe"}, claims.Audience)
+}
+
+func TestSign_UnsupportedKey(t *testing.T) {
+	p384Key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	_, err := Sign(validClaims(), p384Key)
+
+	assert.NotEqual(t, nil, err)
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	ecdsaPublicKey, _ := x509.MarshalPKIXPublicKey(ecdsaKey.Public())
+	data := append(
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecdsaPublicKey}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)})...,
+	)
+
+	keys, err := ParsePublicKeys(data)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []crypto.PublicKey{ecdsaKey.Public(), &rsaKey.PublicKey}, keys)
+
+	_, err = ParsePublicKeys([]byte{})
+	assert.NotEqual(t, nil, err)
+
+	ecdsaPrivateKey, _ := x509.MarshalECPrivateKey(ecdsaKey)
+	_, err = ParsePublicKeys(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecdsaPrivateKey}))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	ecdsaPrivateKey, _ := x509.MarshalECPrivateKey(ecdsaKey)
+	pkcs8PrivateKey, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+
+	tests := []struct {
+		name     string
+		block    *pem.Block
+		expected crypto.Signer
+	}{
+		{
+			name:     "PKCS #8",
+			block:    &pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8PrivateKey},
+			expected: rsaKey,
+		},
+		{
+			name:     "PKCS #1",
+			block:    &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)},
+			expected: rsaKey,
+		},
+		{
+			name:     "SEC 1",
+			block:    &pem.Block{Type: "EC PRIVATE KEY", Bytes: ecdsaPrivateKey},
+			expected: ecdsaKey,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, err := ParsePrivateKey(pem.EncodeToMemory(test.block))
+
+			assert.Equal(t, nil, err)
+			assert.True(t, test.expected.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(key.Public()))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"flag"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/handlers"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to serve the websocket endpoint on")
+	sweepInterval := flag.Duration("sweep-interval", time.Second, "how often to check for games that are out of time")
+	publicKeys := flag.String("public-keys", "", "PEM file of the public keys to verify connection tokens with")
+	issuer := flag.String("issuer", "", "issuer connection tokens must have, if any")
+	audience := flag.String("audience", "", "audience connection tokens must have, if any")
+	flag.Parse()
+
+	utils.Initialize()
+
+	keys, err := readPublicKeys(*publicKeys)
+	if err != nil {
+		log.Fatalf("An error occurred while reading public keys from %s - %s", *publicKeys, err)
+	}
+
+	h := &handlers.Handlers{
+		Games:       db.NewInMemoryGameRepository(),
+		Connections: db.NewInMemoryConnectionRepository(),
+		Players:     db.NewInMemoryPlayerRepository(),
+		MatchQueue:  db.NewInMemoryMatchQueueRepository(),
+		Spectators:  db.NewInMemorySpectatorRepository(),
+		Invitations: db.NewInMemoryInvitationRepository(),
+	}
+	server := NewServer(h, auth.NewVerifier(keys, *issuer, *audience))
+	go sweepClocks(h, *sweepInterval)
+
+	log.Infof("Serving websocket API on ws://%s/", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server))
+}
+
+func readPublicKeys(path string) ([]crypto.PublicKey, error) {
+	if path == "" {
+		return nil, fmt.Errorf("-public-keys is required")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ParsePublicKeys(data)
+}
+
+// sweepClocks stands in for the scheduled sweep-clocks lambda
+func sweepClocks(h *handlers.Handlers, interval time.Duration) {
+	for range time.Tick(interval) {
+		_ = h.SweepClocks(context.Background(), events.CloudWatchEvent{})
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/handlers"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const stage = "local"
+
+type ConnectionGoneError struct {
+	connectionId string
+}
+
+func (e *ConnectionGoneError) Error() string {
+	return fmt.Sprintf("connection %s is no longer open", e.connectionId)
+}
+
+type localConnection struct {
+	id                string
+	connectedAt       time.Time
+	principalId       string
+	authorizerContext map[string]interface{}
+	socket            *websocket.Conn
+	writeMutex        sync.Mutex
+}
+
+func (c *localConnection) write(data []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return c.socket.WriteMessage(websocket.TextMessage, data)
+}
+
+// Server mimics API Gateway's websocket API, routing each message to a handler by its action the same way
+// RouteSelectionExpression $request.body.action does, after authorizing connections the way the $connect authorizer
+// does
+type Server struct {
+	router   *router.Router
+	verifier *auth.Verifier
+	upgrader websocket.Upgrader
+
+	mutex       sync.Mutex
+	connections map[string]*localConnection
+}
+
+func NewServer(h *handlers.Handlers, verifier *auth.Verifier) *Server {
+	server := &Server{
+		verifier: verifier,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true
+			},
+		},
+		connections: map[string]*localConnection{},
+	}
+
+	h.Messenger = server
+	server.router = h.Router()
+
+	return server
+}
+
+func (s *Server) SendMessage(_ events.APIGatewayWebsocketProxyRequest, messageTo string, message interface{}) error {
+	responseBodySerialized, err := json.MarshalIndent(message, "", "")
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	connection, ok := s.connections[messageTo]
+	s.mutex.Unlock()
+	if !ok {
+		return &ConnectionGoneError{connectionId: messageTo}
+	}
+
+	return connection.write(responseBodySerialized)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	connection := &localConnection{
+		id:          newConnectionId(),
+		connectedAt: time.Now(),
+	}
+
+	authorizerResponse, err := s.verifier.Authorize(r.Context(), events.APIGatewayCustomAuthorizerRequestTypeRequest{
+		Type:                  "REQUEST",
+		Headers:               singleValueHeaders(r.Header),
+		MultiValueHeaders:     r.Header,
+		QueryStringParameters: singleValueHeaders(r.URL.Query()),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	connection.principalId = authorizerResponse.PrincipalID
+	connection.authorizerContext = authorizerResponse.Context
+
+	// API Gateway only completes the handshake if $connect succeeds, so run it before upgrading
+	response := s.invoke(r, connection, "$connect", "CONNECT", "")
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		http.Error(w, response.Body, response.StatusCode)
+		return
+	}
+
+	socket, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Errorf("An error occurred while upgrading connection %s - %s", connection.id, err)
+		s.invoke(r, connection, "$disconnect", "DISCONNECT", "")
+		return
+	}
+	connection.socket = socket
+
+	s.mutex.Lock()
+	s.connections[connection.id] = connection
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.connections, connection.id)
+		s.mutex.Unlock()
+
+		_ = socket.Close()
+		s.invoke(r, connection, "$disconnect", "DISCONNECT", "")
+	}()
+
+	for {
+		_, message, err := socket.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		go s.handleMessage(r, connection, string(message))
+	}
+}
+
+func (s *Server) handleMessage(r *http.Request, connection *localConnection, body string) {
+	var request struct {
+		Action string `json:"action"`
+	}
+	routeKey := "$default"
+	if err := json.Unmarshal([]byte(body), &request); err == nil {
+		if s.router.Has(request.Action) {
+			routeKey = request.Action
+		}
+	}
+
+	response := s.invoke(r, connection, routeKey, "MESSAGE", body)
+	if response.Body == "" {
+		return
+	}
+
+	if err := connection.write([]byte(response.Body)); err != nil {
+		log.Errorf("An error occurred while responding to connection %s - %s", connection.id, err)
+	}
+}
+
+func (s *Server) invoke(r *http.Request, connection *localConnection, routeKey string, eventType string, body string) events.APIGatewayProxyResponse {
+	websocketEvent := events.APIGatewayWebsocketProxyRequest{
+		Body:                  body,
+		Headers:               singleValueHeaders(r.Header),
+		MultiValueHeaders:     r.Header,
+		QueryStringParameters: singleValueHeaders(r.URL.Query()),
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			Authorizer:       auth.AuthorizerContext(connection.principalId, connection.authorizerContext),
+			ConnectionID:     connection.id,
+			ConnectedAt:      connection.connectedAt.UnixMilli(),
+			DomainName:       r.Host,
+			EventType:        eventType,
+			MessageDirection: "IN",
+			RequestTimeEpoch: time.Now().UnixMilli(),
+			RouteKey:         routeKey,
+			Stage:            stage,
+		},
+	}
+
+	response, err := s.router.Route(context.Background(), websocketEvent)
+	if err != nil {
+		log.Errorf("Route %s failed for connection %s - %s", routeKey, connection.id, err)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusInternalServerError}
+	}
+
+	return response
+}
+
+func singleValueHeaders(values map[string][]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range values {
+		if len(value) > 0 {
+			result[key] = value[0]
+		}
+	}
+	return result
+}
+
+// newConnectionId generates an ID in the same shape as API Gateway's, e.g. "ZlXFrd8BoAMCKTA="
+func newConnectionId() string {
+	bytes := make([]byte, 10)
+	if _, err := rand.Read(bytes); err != nil {
+		panic(err)
+	}
+	return base64.StdEncoding.EncodeToString(bytes)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/handlers"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var signingKey = newSigningKey()
+
+func newSigningKey() *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// newToken names playerX "Player X", and so on
+func newToken(t *testing.T, key *ecdsa.PrivateKey, playerId string) string {
+	claims := auth.Claims{
+		Subject:   playerId,
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Name:      "Player " + strings.TrimPrefix(playerId, "player"),
+	}
+	token, err := auth.Sign(claims, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func startServer(t *testing.T) (*Server, *db.InMemoryConnectionRepository, string) {
+	connections := db.NewInMemoryConnectionRepository()
+	server := NewServer(&handlers.Handlers{
+		Games:       db.NewInMemoryGameRepository(),
+		Connections: connections,
+		Players:     db.NewInMemoryPlayerRepository(),
+		MatchQueue:  db.NewInMemoryMatchQueueRepository(),
+		Spectators:  db.NewInMemorySpectatorRepository(),
+		Invitations: db.NewInMemoryInvitationRepository(),
+	}, auth.NewVerifier([]crypto.PublicKey{signingKey.Public()}, "", ""))
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	return server, connections, "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+func dial(t *testing.T, server *Server, url string, playerId string) (*websocket.Conn, string) {
+	existing := server.connectionIds()
+
+	socket, _, err := websocket.DefaultDialer.Dial(url+"?token="+newToken(t, signingKey, playerId), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = socket.Close()
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, id := range server.connectionIds() {
+			if !contains(existing, id) {
+				return socket, id
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("connection was not registered")
+	return nil, ""
+}
+
+type envelope struct {
+	Version   int             `json:"version"`
+	Type      string          `json:"type"`
+	RequestId string          `json:"requestId"`
+	Payload   json.RawMessage `json:"payload"`
+	Error     *utils.Error    `json:"error"`
+}
+
+func readEnvelope(t *testing.T, socket *websocket.Conn) envelope {
+	_ = socket.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, message, err := socket.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var e envelope
+	if err := json.Unmarshal(message, &e); err != nil {
+		t.Fatalf("message %s is not an envelope - %s", message, err)
+	}
+	return e
+}
+
+func readGame(t *testing.T, socket *websocket.Conn) game.Game {
+	e := readEnvelope(t, socket)
+
+	var g game.Game
+	if err := json.Unmarshal(e.Payload, &g); err != nil {
+		t.Fatalf("payload %s is not a game - %s", e.Payload, err)
+	}
+	return g
+}
+
+func (s *Server) connectionIds() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var ids []string
+	for id := range s.connections {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestServer_ConnectAndDisconnect(t *testing.T) {
+	server, connections, url := startServer(t)
+
+	socket, connectionId := dial(t, server, url, "playerX")
+
+	c, err := connections.GetConnection(connectionId)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "playerX", c.PlayerId)
+
+	_ = socket.Close()
+	assert.Eventually(t, func() bool {
+		_, err := connections.GetConnection(connectionId)
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestServer_RoutesActionsAndDeliversPushes(t *testing.T) {
+	server, _, url := startServer(t)
+	playerX, _ := dial(t, server, url, "playerX")
+	playerO, _ := dial(t, server, url, "playerO")
+
+	// playerX is told that playerO has joined the lobby
+	assert.Equal(t, string(utils.PlayerJoined), readEnvelope(t, playerX).Type)
+
+	_ = playerX.WriteMessage(websocket.TextMessage, []byte(`{"action": "create-game", "requestId": "abc", "playerO": "playerO"}`))
+
+	response := readEnvelope(t, playerX)
+	push := readEnvelope(t, playerO)
+	assert.Equal(t, "create-game", response.Type)
+	assert.Equal(t, "abc", response.RequestId)
+	assert.Equal(t, string(utils.GameStarted), push.Type)
+	assert.Equal(t, "", push.RequestId)
+
+	var createdGame, pushedGame game.Game
+	_ = json.Unmarshal(response.Payload, &createdGame)
+	_ = json.Unmarshal(push.Payload, &pushedGame)
+	assert.Equal(t, "playerX", createdGame.PlayerX)
+	assert.Equal(t, "playerO", createdGame.PlayerO)
+	assert.Equal(t, createdGame.Id, pushedGame.Id)
+
+	_ = playerX.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"action": "make-move", "id": "%s", "move": 4}`, createdGame.Id)))
+
+	movedGame := readGame(t, playerX)
+	pushedGame = readGame(t, playerO)
+	assert.Equal(t, game.X, movedGame.Board[1][1])
+	assert.Equal(t, movedGame.Board, pushedGame.Board)
+}
+
+func TestServer_Lobby(t *testing.T) {
+	server, _, url := startServer(t)
+	playerX, _ := dial(t, server, url, "playerX")
+	playerO, _ := dial(t, server, url, "playerO")
+	readEnvelope(t, playerX)
+
+	_ = playerX.WriteMessage(websocket.TextMessage, []byte(`{"action": "create-game", "open": true, "isPublic": true}`))
+	readEnvelope(t, playerX)
+	push := readEnvelope(t, playerO)
+
+	_ = playerO.WriteMessage(websocket.TextMessage, []byte(`{"action": "list-lobby"}`))
+	response := readEnvelope(t, playerO)
+
+	assert.Equal(t, string(utils.LobbyUpdated), push.Type)
+	assert.Equal(t, "list-lobby", response.Type)
+	var pushedLobby, lobby handlers.Lobby
+	_ = json.Unmarshal(push.Payload, &pushedLobby)
+	_ = json.Unmarshal(response.Payload, &lobby)
+	assert.Equal(t, pushedLobby, lobby)
+	assert.Equal(t, 1, len(lobby.Invitations))
+	assert.Equal(t, "Player X", lobby.Invitations[0].DisplayName)
+	assert.ElementsMatch(t, []handlers.LobbyPlayer{
+		{Id: "playerX", DisplayName: "Player X"},
+		{Id: "playerO", DisplayName: "Player O"},
+	}, lobby.Players)
+}
+
+func TestServer_UnknownActionFallsBackToDefault(t *testing.T) {
+	server, _, url := startServer(t)
+	socket, _ := dial(t, server, url, "playerX")
+
+	_ = socket.WriteMessage(websocket.TextMessage, []byte(`{"action": "does-not-exist", "requestId": "1"}`))
+
+	e := readEnvelope(t, socket)
+	assert.Equal(t, "does-not-exist", e.Type)
+	assert.Equal(t, "1", e.RequestId)
+	assert.Equal(t, &utils.Error{Code: "UNKNOWN_ACTION", Message: "Action (does-not-exist) does not correspond to any routes"}, e.Error)
+}
+
+func TestServer_RejectsConnectionWithoutToken(t *testing.T) {
+	_, _, url := startServer(t)
+
+	_, response, err := websocket.DefaultDialer.Dial(url, nil)
+
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestServer_RejectsTokenSignedWithUntrustedKey(t *testing.T) {
+	_, _, url := startServer(t)
+
+	_, response, err := websocket.DefaultDialer.Dial(url+"?token="+newToken(t, newSigningKey(), "playerX"), nil)
+
+	assert.NotEqual(t, nil, err)
+	assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+}
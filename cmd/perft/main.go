@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	variant := flag.String("variant", string(game.Classic), "variant to play")
+	rows := flag.Int("rows", 0, "number of rows on the board, or zero for the variant's default")
+	columns := flag.Int("columns", 0, "number of columns on the board, or zero for the variant's default")
+	winLength := flag.Int("win-length", 0, "number in a row needed to win, or zero for the variant's default")
+	moves := flag.String("moves", "", "comma-separated moves to play before counting, e.g. 4,0")
+	depth := flag.Int("depth", game.PerftToEnd, "number of plies to count to, or -1 to play every game out to the end")
+	flag.Parse()
+
+	g, err := game.NewVariantGame(game.Variant(*variant), "X", "O", *rows, *columns, *winLength)
+	if err != nil {
+		fail(err)
+	}
+	if *moves != "" {
+		for _, move := range strings.Split(*moves, ",") {
+			square, err := strconv.Atoi(strings.TrimSpace(move))
+			if err != nil {
+				fail(fmt.Errorf("%s is not a move", move))
+			}
+			if err = g.MakeMove(g.CurrentPlayer(), square); err != nil {
+				fail(err)
+			}
+		}
+	}
+
+	start := time.Now()
+	result := game.Perft(g, *depth)
+	elapsed := time.Since(start)
+
+	fmt.Printf("%-6s %12s\n", "depth", "nodes")
+	for ply, nodes := range result.Nodes {
+		fmt.Printf("%-6d %12d\n", ply, nodes)
+	}
+	fmt.Println()
+	fmt.Printf("games                        %12d\n", result.Games())
+	fmt.Printf("X wins                       %12d\n", result.XWins)
+	fmt.Printf("O wins                       %12d\n", result.OWins)
+	fmt.Printf("draws                        %12d\n", result.Draws)
+	fmt.Printf("terminal positions           %12d\n", result.TerminalPositions)
+	fmt.Printf("  up to symmetry             %12d\n", result.SymmetricTerminalPositions)
+	fmt.Printf("time                         %12s\n", elapsed.Round(time.Millisecond))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"os"
+	"time"
+)
+
+// token signs a connection token with a local key, standing in for an identity provider when running locally
+func main() {
+	privateKey := flag.String("private-key", "", "PEM file of the RSA or P-256 ECDSA key to sign the token with")
+	subject := flag.String("subject", "", "player ID to issue the token to")
+	name := flag.String("name", "", "name to show the player as to other players, if any")
+	issuer := flag.String("issuer", "", "issuer to name in the token, if any")
+	audience := flag.String("audience", "", "audience to name in the token, if any")
+	validFor := flag.Duration("valid-for", 24*time.Hour, "how long the token is valid for")
+	flag.Parse()
+
+	if *privateKey == "" || *subject == "" {
+		fail(fmt.Errorf("-private-key and -subject are required"))
+	}
+
+	data, err := os.ReadFile(*privateKey)
+	if err != nil {
+		fail(err)
+	}
+	key, err := auth.ParsePrivateKey(data)
+	if err != nil {
+		fail(err)
+	}
+
+	issuedAt := time.Now()
+	claims := auth.Claims{
+		Subject:   *subject,
+		Issuer:    *issuer,
+		ExpiresAt: issuedAt.Add(*validFor).Unix(),
+		IssuedAt:  issuedAt.Unix(),
+		Name:      *name,
+	}
+	if *audience != "" {
+		claims.Audience = auth.Audience{*audience}
+	}
+
+	token, err := auth.Sign(claims, key)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Println(token)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
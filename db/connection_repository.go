@@ -4,23 +4,45 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"os"
 	"time"
 )
 
 type Connection struct {
-	Id  string
-	Ttl int64
+	Id       string
+	PlayerId string
+	Ttl      int64
 }
 
 var connectionTableName = os.Getenv("CONNECTION_TABLE_NAME")
 
-func CreateConnection(id string) (Connection, error) {
+type ConnectionRepository interface {
+	CreateConnection(id string, playerId string) (Connection, error)
+	GetConnection(id string) (Connection, error)
+	DeleteConnection(id string) (Connection, error)
+	RefreshTtl(id string) (Connection, error)
+}
+
+type DynamoConnectionRepository struct {
+	svc       dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+func NewDynamoConnectionRepository(svc dynamodbiface.DynamoDBAPI) *DynamoConnectionRepository {
+	return &DynamoConnectionRepository{
+		svc:       svc,
+		tableName: connectionTableName,
+	}
+}
+
+func (r *DynamoConnectionRepository) CreateConnection(id string, playerId string) (Connection, error) {
 
 	ttlExpiryTime := generateUnixTimestampIn20Minutes()
 	c := Connection{
-		Id:  id,
-		Ttl: ttlExpiryTime,
+		Id:       id,
+		PlayerId: playerId,
+		Ttl:      ttlExpiryTime,
 	}
 
 	result, err := dynamodbattribute.MarshalMap(c)
@@ -29,21 +51,21 @@ func CreateConnection(id string) (Connection, error) {
 	}
 
 	input := &dynamodb.PutItemInput{
-		TableName: aws.String(connectionTableName),
+		TableName: aws.String(r.tableName),
 		Item:      result,
 	}
-	if _, err := svc.PutItem(input); err != nil {
+	if _, err := r.svc.PutItem(input); err != nil {
 		return Connection{}, err
 	}
 
 	return c, nil
 }
 
-func GetConnection(id string) (Connection, error) {
+func (r *DynamoConnectionRepository) GetConnection(id string) (Connection, error) {
 	c := Connection{}
 
 	input := &dynamodb.GetItemInput{
-		TableName: aws.String(connectionTableName),
+		TableName: aws.String(r.tableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"Id": {
 				S: aws.String(id),
@@ -51,12 +73,12 @@ func GetConnection(id string) (Connection, error) {
 		},
 	}
 
-	if result, err := svc.GetItem(input); err != nil {
+	if result, err := r.svc.GetItem(input); err != nil {
 		return Connection{}, err
 
 	} else if result.Item == nil {
 		return Connection{}, &EntityDoesNotExistError{
-			entityType: connectionTableName,
+			entityType: r.tableName,
 			id:         id,
 		}
 
@@ -68,9 +90,9 @@ func GetConnection(id string) (Connection, error) {
 	}
 }
 
-func DeleteConnection(id string) (Connection, error) {
+func (r *DynamoConnectionRepository) DeleteConnection(id string) (Connection, error) {
 	input := &dynamodb.DeleteItemInput{
-		TableName: aws.String(connectionTableName),
+		TableName: aws.String(r.tableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"Id": {
 				S: aws.String(id),
@@ -81,14 +103,14 @@ func DeleteConnection(id string) (Connection, error) {
 
 	c := Connection{}
 
-	result, err := svc.DeleteItem(input)
+	result, err := r.svc.DeleteItem(input)
 	if err != nil {
 		return c, err
 	}
 
-	if result == nil {
+	if len(result.Attributes) == 0 {
 		return c, &EntityDoesNotExistError{
-			entityType: connectionTableName,
+			entityType: r.tableName,
 			id:         id,
 		}
 	}
@@ -101,7 +123,7 @@ func DeleteConnection(id string) (Connection, error) {
 	return c, nil
 }
 
-func RefreshTtl(id string) (Connection, error) {
+func (r *DynamoConnectionRepository) RefreshTtl(id string) (Connection, error) {
 	result, err := dynamodbattribute.MarshalMap(Connection{
 		Id:  id,
 		Ttl: generateUnixTimestampIn20Minutes(),
@@ -115,7 +137,7 @@ func RefreshTtl(id string) (Connection, error) {
 	}
 
 	input := &dynamodb.UpdateItemInput{
-		TableName:                 aws.String(connectionTableName),
+		TableName:                 aws.String(r.tableName),
 		ExpressionAttributeValues: expressionAttributeValues,
 		Key: map[string]*dynamodb.AttributeValue{
 			"Id": {
@@ -127,7 +149,7 @@ func RefreshTtl(id string) (Connection, error) {
 	}
 
 	updatedConnection := Connection{}
-	if result, err := svc.UpdateItem(input); err != nil {
+	if result, err := r.svc.UpdateItem(input); err != nil {
 		return Connection{}, err
 	} else if err := dynamodbattribute.UnmarshalMap(result.Attributes, &updatedConnection); err != nil {
 		return Connection{}, err
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInMemoryConnectionRepository_CreateAndGetConnection(t *testing.T) {
+	repository := NewInMemoryConnectionRepository()
+
+	created, err := repository.CreateConnection("connection", "player")
+	assert.Equal(t, nil, err)
+
+	retrieved, err := repository.GetConnection("connection")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, created, retrieved)
+}
+
+func TestInMemoryConnectionRepository_GetConnection_DoesNotExist(t *testing.T) {
+	repository := NewInMemoryConnectionRepository()
+
+	_, err := repository.GetConnection("connection")
+
+	assert.Equal(t, &EntityDoesNotExistError{entityType: "connection", id: "connection"}, err)
+}
+
+func TestInMemoryConnectionRepository_DeleteConnection(t *testing.T) {
+	repository := NewInMemoryConnectionRepository()
+	created, _ := repository.CreateConnection("connection", "player")
+
+	deleted, err := repository.DeleteConnection("connection")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, created, deleted)
+
+	_, err = repository.DeleteConnection("connection")
+	assert.Equal(t, &EntityDoesNotExistError{entityType: "connection", id: "connection"}, err)
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fakeDynamoDB stores items in memory and understands just enough of the expression syntax used by this package
+type fakeDynamoDB struct {
+	dynamodbiface.DynamoDBAPI
+
+	mutex  sync.Mutex
+	tables map[string]map[string]map[string]*dynamodb.AttributeValue
+}
+
+func newFakeDynamoDB() *fakeDynamoDB {
+	return &fakeDynamoDB{
+		tables: map[string]map[string]map[string]*dynamodb.AttributeValue{},
+	}
+}
+
+func (f *fakeDynamoDB) table(name *string) map[string]map[string]*dynamodb.AttributeValue {
+	if _, ok := f.tables[*name]; !ok {
+		f.tables[*name] = map[string]map[string]*dynamodb.AttributeValue{}
+	}
+	return f.tables[*name]
+}
+
+func (f *fakeDynamoDB) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	table := f.table(input.TableName)
+	id := *input.Item["Id"].S
+	if !evaluateConditionExpression(input.ConditionExpression, table[id], input.ExpressionAttributeValues) {
+		return nil, conditionalCheckFailed()
+	}
+
+	table[id] = input.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return &dynamodb.GetItemOutput{Item: f.table(input.TableName)[*input.Key["Id"].S]}, nil
+}
+
+func (f *fakeDynamoDB) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	table := f.table(input.TableName)
+	id := *input.Key["Id"].S
+	item := table[id]
+
+	if !evaluateConditionExpression(input.ConditionExpression, item, input.ExpressionAttributeValues) {
+		return nil, conditionalCheckFailed()
+	}
+
+	updated := map[string]*dynamodb.AttributeValue{"Id": {S: aws.String(id)}}
+	for name, value := range item {
+		updated[name] = value
+	}
+
+	updateExpression := strings.TrimPrefix(*input.UpdateExpression, "SET ")
+	if i := strings.Index(updateExpression, " REMOVE "); i != -1 {
+		for _, name := range strings.Split(updateExpression[i+len(" REMOVE "):], ", ") {
+			delete(updated, name)
+		}
+		updateExpression = updateExpression[:i]
+	}
+
+	for _, assignment := range strings.Split(updateExpression, ", ") {
+		parts := strings.Split(assignment, " = ")
+		name := parts[0]
+		if alias, ok := input.ExpressionAttributeNames[name]; ok {
+			name = *alias
+		}
+		updated[name] = input.ExpressionAttributeValues[parts[1]]
+	}
+
+	table[id] = updated
+	return &dynamodb.UpdateItemOutput{Attributes: updated}, nil
+}
+
+func (f *fakeDynamoDB) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	table := f.table(input.TableName)
+	id := *input.Key["Id"].S
+	item := table[id]
+	if !evaluateConditionExpression(input.ConditionExpression, item, input.ExpressionAttributeValues) {
+		return nil, conditionalCheckFailed()
+	}
+
+	delete(table, id)
+	return &dynamodb.DeleteItemOutput{Attributes: item}, nil
+}
+
+func conditionalCheckFailed() error {
+	return awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+}
+
+func evaluateConditionExpression(conditionExpression *string, item map[string]*dynamodb.AttributeValue, values map[string]*dynamodb.AttributeValue) bool {
+	if conditionExpression == nil {
+		return true
+	}
+	for _, condition := range strings.Split(*conditionExpression, " AND ") {
+		if !evaluateCondition(condition, item, values) {
+			return false
+		}
+	}
+	return true
+}
+
+func evaluateCondition(condition string, item map[string]*dynamodb.AttributeValue, values map[string]*dynamodb.AttributeValue) bool {
+	switch {
+	case strings.HasPrefix(condition, "attribute_exists("):
+		name := strings.TrimSuffix(strings.TrimPrefix(condition, "attribute_exists("), ")")
+		return item != nil && item[name] != nil
+	case strings.HasPrefix(condition, "attribute_not_exists("):
+		name := strings.TrimSuffix(strings.TrimPrefix(condition, "attribute_not_exists("), ")")
+		return item == nil || item[name] == nil
+	case strings.Contains(condition, " > "):
+		parts := strings.Split(condition, " > ")
+		if item == nil || item[parts[0]] == nil {
+			return false
+		}
+		actual, _ := strconv.ParseInt(*item[parts[0]].N, 10, 64)
+		expected, _ := strconv.ParseInt(*values[parts[1]].N, 10, 64)
+		return actual > expected
+	default:
+		parts := strings.Split(condition, " = ")
+		return item != nil && item[parts[0]] != nil && *item[parts[0]].N == *values[parts[1]].N
+	}
+}
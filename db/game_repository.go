@@ -1,19 +1,51 @@
 package db
 
 import (
+	"fmt"
 	"github.com/Jake-Baum/tic-tac-toe/game"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/google/uuid"
 	"os"
+	"strconv"
+	"time"
 )
 
 var gameTableName = os.Getenv("GAME_TABLE_NAME")
 
-func CreateGame(g game.Game) (game.Game, error) {
+type GameRepository interface {
+	CreateGame(g game.Game) (game.Game, error)
+	GetGame(id string) (game.Game, error)
+	UpdateGame(g game.Game) (game.Game, error)
+	ListGamesForPlayer(playerId string) ([]game.Game, error)
+	ListGamesOutOfTime(at time.Time) ([]game.Game, error)
+}
+
+var gamePlayerIndexNames = map[string]string{
+	"PlayerX": "PlayerX-index",
+	"PlayerO": "PlayerO-index",
+}
+
+const gameDeadlineIndexName = "Status-Deadline-index"
+
+type DynamoGameRepository struct {
+	svc       dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+func NewDynamoGameRepository(svc dynamodbiface.DynamoDBAPI) *DynamoGameRepository {
+	return &DynamoGameRepository{
+		svc:       svc,
+		tableName: gameTableName,
+	}
+}
+
+func (r *DynamoGameRepository) CreateGame(g game.Game) (game.Game, error) {
 	id := uuid.New().String()
 	g.Id = id
+	g.Version = 1
 
 	result, err := dynamodbattribute.MarshalMap(g)
 	if err != nil {
@@ -21,21 +53,21 @@ func CreateGame(g game.Game) (game.Game, error) {
 	}
 
 	input := &dynamodb.PutItemInput{
-		TableName: aws.String(gameTableName),
+		TableName: aws.String(r.tableName),
 		Item:      result,
 	}
-	if _, err := svc.PutItem(input); err != nil {
+	if _, err := r.svc.PutItem(input); err != nil {
 		return game.Game{}, err
 	}
 
 	return g, nil
 }
 
-func GetGame(id string) (game.Game, error) {
+func (r *DynamoGameRepository) GetGame(id string) (game.Game, error) {
 	g := game.Game{}
 
 	input := &dynamodb.GetItemInput{
-		TableName: aws.String(gameTableName),
+		TableName: aws.String(r.tableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"Id": {
 				S: aws.String(id),
@@ -43,12 +75,12 @@ func GetGame(id string) (game.Game, error) {
 		},
 	}
 
-	if result, err := svc.GetItem(input); err != nil {
+	if result, err := r.svc.GetItem(input); err != nil {
 		return game.Game{}, err
 
 	} else if result.Item == nil {
 		return game.Game{}, &EntityDoesNotExistError{
-			entityType: gameTableName,
+			entityType: r.tableName,
 			id:         id,
 		}
 
@@ -60,31 +92,72 @@ func GetGame(id string) (game.Game, error) {
 	}
 }
 
-func UpdateGame(g game.Game) (game.Game, error) {
+func (r *DynamoGameRepository) UpdateGame(g game.Game) (game.Game, error) {
 	result, err := dynamodbattribute.MarshalMap(g)
 	if err != nil {
 		return game.Game{}, err
 	}
 
 	expressionAttributeValues := map[string]*dynamodb.AttributeValue{
-		":Board":       result["Board"],
-		":CurrentTurn": result["CurrentTurn"],
+		":Board":         result["Board"],
+		":CurrentTurn":   result["CurrentTurn"],
+		":Moves":         result["Moves"],
+		":Status":        result["Status"],
+		":Winner":        result["Winner"],
+		":WinningLine":   result["WinningLine"],
+		":RemainingX":    result["RemainingX"],
+		":RemainingO":    result["RemainingO"],
+		":DrawOfferedBy": result["DrawOfferedBy"],
+		":NextGameId":    result["NextGameId"],
+		":ActiveBoard":   result["ActiveBoard"],
+		":LocalStatuses": result["LocalStatuses"],
+		":Version": {
+			N: aws.String(strconv.Itoa(g.Version + 1)),
+		},
+	}
+
+	// Games created before versioning have no Version attribute, so there is nothing to compare against
+	conditionExpression := "attribute_exists(Id) AND attribute_not_exists(Version)"
+	if g.Version != 0 {
+		conditionExpression = "attribute_exists(Id) AND Version = :ExpectedVersion"
+		expressionAttributeValues[":ExpectedVersion"] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.Itoa(g.Version)),
+		}
+	}
+
+	// Deadline is removed rather than zeroed once the clock stops, so the game drops out of the deadline index
+	updateExpression := "SET Board = :Board, CurrentTurn = :CurrentTurn, Moves = :Moves, #Status = :Status, Winner = :Winner, WinningLine = :WinningLine, RemainingX = :RemainingX, RemainingO = :RemainingO, DrawOfferedBy = :DrawOfferedBy, NextGameId = :NextGameId, ActiveBoard = :ActiveBoard, LocalStatuses = :LocalStatuses, Version = :Version"
+	if g.Deadline != 0 {
+		updateExpression += ", Deadline = :Deadline"
+		expressionAttributeValues[":Deadline"] = result["Deadline"]
+	} else {
+		updateExpression += " REMOVE Deadline"
 	}
 
 	input := &dynamodb.UpdateItemInput{
-		TableName:                 aws.String(gameTableName),
+		TableName:                 aws.String(r.tableName),
 		ExpressionAttributeValues: expressionAttributeValues,
+		ExpressionAttributeNames: map[string]*string{
+			"#Status": aws.String("Status"), // STATUS is a DynamoDB reserved word
+		},
 		Key: map[string]*dynamodb.AttributeValue{
 			"Id": {
 				S: aws.String(g.Id),
 			},
 		},
-		ReturnValues:     aws.String("ALL_NEW"),
-		UpdateExpression: aws.String("SET Board = :Board, CurrentTurn = :CurrentTurn"),
+		ConditionExpression: aws.String(conditionExpression),
+		ReturnValues:        aws.String("ALL_NEW"),
+		UpdateExpression:    aws.String(updateExpression),
 	}
 
 	updatedGame := game.Game{}
-	if result, err := svc.UpdateItem(input); err != nil {
+	if result, err := r.svc.UpdateItem(input); err != nil {
+		if isConditionalCheckFailed(err) {
+			return game.Game{}, &ConcurrentModificationError{
+				entityType: r.tableName,
+				id:         g.Id,
+			}
+		}
 		return game.Game{}, err
 	} else if err := dynamodbattribute.UnmarshalMap(result.Attributes, &updatedGame); err != nil {
 		return game.Game{}, err
@@ -92,3 +165,76 @@ func UpdateGame(g game.Game) (game.Game, error) {
 		return updatedGame, nil
 	}
 }
+
+func (r *DynamoGameRepository) ListGamesForPlayer(playerId string) ([]game.Game, error) {
+	games := []game.Game{}
+
+	for _, attributeName := range []string{"PlayerX", "PlayerO"} {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String(gamePlayerIndexNames[attributeName]),
+			KeyConditionExpression: aws.String(fmt.Sprintf("%s = :PlayerId", attributeName)),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":PlayerId": {
+					S: aws.String(playerId),
+				},
+			},
+		}
+
+		var unmarshalErr error
+		err := r.svc.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+			var pageGames []game.Game
+			if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageGames); unmarshalErr != nil {
+				return false
+			}
+			games = append(games, pageGames...)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		} else if unmarshalErr != nil {
+			return nil, unmarshalErr
+		}
+	}
+
+	return games, nil
+}
+
+// ListGamesOutOfTime finds the games in progress whose current player's clock had run out by the given time
+func (r *DynamoGameRepository) ListGamesOutOfTime(at time.Time) ([]game.Game, error) {
+	games := []game.Game{}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(gameDeadlineIndexName),
+		KeyConditionExpression: aws.String("#Status = :Status AND Deadline <= :At"),
+		ExpressionAttributeNames: map[string]*string{
+			"#Status": aws.String("Status"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Status": {
+				S: aws.String(string(game.InProgress)),
+			},
+			":At": {
+				N: aws.String(strconv.FormatInt(at.UnixNano(), 10)),
+			},
+		},
+	}
+
+	var unmarshalErr error
+	err := r.svc.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageGames []game.Game
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageGames); unmarshalErr != nil {
+			return false
+		}
+		games = append(games, pageGames...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	} else if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	return games, nil
+}
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDynamoGameRepository_CreateGame_SetsVersion(t *testing.T) {
+	repository := NewDynamoGameRepository(newFakeDynamoDB())
+
+	g, err := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, g.Version)
+}
+
+func TestDynamoGameRepository_UpdateGame_IncrementsVersion(t *testing.T) {
+	repository := NewDynamoGameRepository(newFakeDynamoDB())
+	g, _ := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	_ = g.MakeMove("playerX", 4)
+	updatedGame, err := repository.UpdateGame(g)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, updatedGame.Version)
+	assert.Equal(t, game.X, updatedGame.Board[1][1])
+}
+
+func TestDynamoGameRepository_UpdateGame_Clock(t *testing.T) {
+	fake := newFakeDynamoDB()
+	repository := NewDynamoGameRepository(fake)
+	newGame := game.NewGame("playerX", "playerO")
+	_ = newGame.StartClock(game.TimeControl{Type: game.Total, Limit: time.Minute})
+	g, _ := repository.CreateGame(*newGame)
+
+	_ = g.MakeMove("playerX", 4)
+	updatedGame, err := repository.UpdateGame(g)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, g.RemainingX, updatedGame.RemainingX)
+	assert.Equal(t, g.Deadline, updatedGame.Deadline)
+	assert.NotNil(t, fake.tables[gameTableName][g.Id]["Deadline"])
+}
+
+func TestDynamoGameRepository_UpdateGame_StoppedClockIsRemoved(t *testing.T) {
+	fake := newFakeDynamoDB()
+	repository := NewDynamoGameRepository(fake)
+	newGame := game.NewGame("playerX", "playerO")
+	_ = newGame.StartClock(game.TimeControl{Type: game.PerMove, Limit: time.Minute})
+	g, _ := repository.CreateGame(*newGame)
+
+	g.Status = game.TimedOut
+	g.Deadline = 0
+	updatedGame, err := repository.UpdateGame(g)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(0), updatedGame.Deadline)
+	assert.Nil(t, fake.tables[gameTableName][g.Id]["Deadline"])
+}
+
+func TestDynamoGameRepository_UpdateGame_DrawOfferAndRematch(t *testing.T) {
+	repository := NewDynamoGameRepository(newFakeDynamoDB())
+	g, _ := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	_ = g.OfferDraw("playerX")
+	g.NextGameId = "next"
+	updatedGame, err := repository.UpdateGame(g)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, game.X, updatedGame.DrawOfferedBy)
+	assert.Equal(t, "next", updatedGame.NextGameId)
+}
+
+func TestDynamoGameRepository_CreateGame_Variant(t *testing.T) {
+	repository := NewDynamoGameRepository(newFakeDynamoDB())
+	newGame, _ := game.NewVariantGame(game.Numerical, "playerX", "playerO", 0, 0, 0)
+	g, _ := repository.CreateGame(*newGame)
+
+	_ = g.MakeMove("playerX", game.NumericalMove(4, 5))
+	_, _ = repository.UpdateGame(g)
+	storedGame, err := repository.GetGame(g.Id)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, game.Numerical, storedGame.Variant)
+	assert.Equal(t, game.Piece("5"), storedGame.Board[1][1])
+}
+
+func TestDynamoGameRepository_UpdateGame_Ultimate(t *testing.T) {
+	repository := NewDynamoGameRepository(newFakeDynamoDB())
+	g, _ := repository.CreateGame(*game.NewUltimateGame("playerX", "playerO"))
+
+	_ = g.MakeMove("playerX", game.UltimateMove(4, 2))
+	updatedGame, err := repository.UpdateGame(g)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, game.Ultimate, updatedGame.Variant)
+	assert.Equal(t, 2, *updatedGame.ActiveBoard)
+	assert.Equal(t, g.LocalStatuses, updatedGame.LocalStatuses)
+	assert.Equal(t, game.X, updatedGame.Board[3][5])
+}
+
+func TestDynamoGameRepository_UpdateGame_StaleVersion(t *testing.T) {
+	repository := NewDynamoGameRepository(newFakeDynamoDB())
+	g, _ := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	first, second := g.Clone(), g.Clone()
+	_ = first.MakeMove("playerX", 4)
+	_ = second.MakeMove("playerX", 0)
+
+	_, err := repository.UpdateGame(*first)
+	assert.Equal(t, nil, err)
+
+	_, err = repository.UpdateGame(*second)
+	assert.Equal(t, &ConcurrentModificationError{entityType: repository.tableName, id: g.Id}, err)
+
+	storedGame, _ := repository.GetGame(g.Id)
+	assert.Equal(t, game.X, storedGame.Board[1][1])
+	assert.Equal(t, game.EMPTY, storedGame.Board[0][0])
+	assert.Equal(t, 2, storedGame.Version)
+}
+
+func TestDynamoGameRepository_UpdateGame_GameWithoutVersion(t *testing.T) {
+	fake := newFakeDynamoDB()
+	repository := NewDynamoGameRepository(fake)
+	g, _ := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+	delete(fake.table(aws.String(gameTableName))[g.Id], "Version")
+	g.Version = 0
+
+	first, second := g.Clone(), g.Clone()
+	_ = first.MakeMove("playerX", 4)
+	_ = second.MakeMove("playerX", 0)
+
+	updatedGame, err := repository.UpdateGame(*first)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, updatedGame.Version)
+
+	_, err = repository.UpdateGame(*second)
+	assert.Equal(t, &ConcurrentModificationError{entityType: repository.tableName, id: g.Id}, err)
+}
+
+func TestDynamoGameRepository_UpdateGame_GameDoesNotExist(t *testing.T) {
+	fake := newFakeDynamoDB()
+	repository := NewDynamoGameRepository(fake)
+	g := game.NewGame("playerX", "playerO")
+	g.Id = "does-not-exist"
+	g.Version = 1
+
+	_, err := repository.UpdateGame(*g)
+
+	assert.Equal(t, &ConcurrentModificationError{entityType: repository.tableName, id: g.Id}, err)
+	assert.Equal(t, map[string]map[string]*dynamodb.AttributeValue{}, fake.table(aws.String(gameTableName)))
+}
+
+func TestGameRepository_UpdateGame_ConcurrentMovesDoNotCorruptBoard(t *testing.T) {
+	repositories := map[string]GameRepository{
+		"dynamo":    NewDynamoGameRepository(newFakeDynamoDB()),
+		"in memory": NewInMemoryGameRepository(),
+	}
+
+	for name, repository := range repositories {
+		t.Run(name, func(t *testing.T) {
+			testConcurrentMovesDoNotCorruptBoard(t, repository)
+		})
+	}
+}
+
+func testConcurrentMovesDoNotCorruptBoard(t *testing.T, repository GameRepository) {
+	g, _ := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	var wg sync.WaitGroup
+	for square := 0; square < 9; square++ {
+		wg.Add(1)
+		go func(square int) {
+			defer wg.Done()
+			for {
+				current, err := repository.GetGame(g.Id)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if err := current.MakeMove(current.CurrentPlayer(), square); err != nil {
+					return
+				}
+				if _, err := repository.UpdateGame(current); err == nil {
+					return
+				} else if _, ok := err.(*ConcurrentModificationError); !ok {
+					t.Error(err)
+					return
+				}
+			}
+		}(square)
+	}
+	wg.Wait()
+
+	storedGame, _ := repository.GetGame(g.Id)
+
+	xCount, oCount := 0, 0
+	for _, row := range storedGame.Board {
+		for _, cell := range row {
+			switch cell {
+			case game.X:
+				xCount++
+			case game.O:
+				oCount++
+			}
+		}
+	}
+
+	assert.Equal(t, len(storedGame.Moves), xCount+oCount)
+	assert.Equal(t, storedGame.Version, len(storedGame.Moves)+1)
+	assert.Contains(t, []int{0, 1}, xCount-oCount)
+
+	replay, err := storedGame.ReplayTo(len(storedGame.Moves))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, storedGame.Board, replay.Board)
+}
+
+func TestInMemoryGameRepository_GetGame_DoesNotExist(t *testing.T) {
+	repository := NewInMemoryGameRepository()
+
+	_, err := repository.GetGame("does-not-exist")
+
+	assert.Equal(t, &EntityDoesNotExistError{entityType: "game", id: "does-not-exist"}, err)
+}
+
+func TestInMemoryGameRepository_GetGame_ReturnsCopy(t *testing.T) {
+	repository := NewInMemoryGameRepository()
+	g, _ := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	retrievedGame, _ := repository.GetGame(g.Id)
+	retrievedGame.Board[0][0] = game.X
+
+	storedGame, _ := repository.GetGame(g.Id)
+	assert.Equal(t, game.EMPTY, storedGame.Board[0][0])
+}
+
+func TestInMemoryGameRepository_UpdateGame_StaleVersion(t *testing.T) {
+	repository := NewInMemoryGameRepository()
+	g, _ := repository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	first, second := g.Clone(), g.Clone()
+	_ = first.MakeMove("playerX", 4)
+	_ = second.MakeMove("playerX", 0)
+
+	updatedGame, err := repository.UpdateGame(*first)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, updatedGame.Version)
+
+	_, err = repository.UpdateGame(*second)
+	assert.Equal(t, &ConcurrentModificationError{entityType: "game", id: g.Id}, err)
+}
+
+func TestInMemoryGameRepository_ListGamesOutOfTime(t *testing.T) {
+	repository := NewInMemoryGameRepository()
+	deadline := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	_, _ = repository.CreateGame(*game.NewGame("playerX", "playerO"))
+	expired := game.NewGame("playerX", "playerO")
+	expired.Deadline = deadline.UnixNano()
+	expired.TimeControl = game.TimeControl{Type: game.PerMove, Limit: time.Minute}
+	expiredGame, _ := repository.CreateGame(*expired)
+	running := expired.Clone()
+	running.Deadline = deadline.Add(time.Second).UnixNano()
+	_, _ = repository.CreateGame(*running)
+
+	games, err := repository.ListGamesOutOfTime(deadline)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []game.Game{expiredGame}, games)
+}
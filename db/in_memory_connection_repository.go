@@ -0,0 +1,78 @@
+package db
+
+import (
+	"sync"
+)
+
+type InMemoryConnectionRepository struct {
+	mutex       sync.Mutex
+	connections map[string]Connection
+}
+
+func NewInMemoryConnectionRepository() *InMemoryConnectionRepository {
+	return &InMemoryConnectionRepository{
+		connections: map[string]Connection{},
+	}
+}
+
+func (r *InMemoryConnectionRepository) CreateConnection(id string, playerId string) (Connection, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c := Connection{
+		Id:       id,
+		PlayerId: playerId,
+		Ttl:      generateUnixTimestampIn20Minutes(),
+	}
+	r.connections[id] = c
+
+	return c, nil
+}
+
+func (r *InMemoryConnectionRepository) GetConnection(id string) (Connection, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c, ok := r.connections[id]
+	if !ok {
+		return Connection{}, &EntityDoesNotExistError{
+			entityType: "connection",
+			id:         id,
+		}
+	}
+
+	return c, nil
+}
+
+func (r *InMemoryConnectionRepository) DeleteConnection(id string) (Connection, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c, ok := r.connections[id]
+	if !ok {
+		return Connection{}, &EntityDoesNotExistError{
+			entityType: "connection",
+			id:         id,
+		}
+	}
+	delete(r.connections, id)
+
+	return c, nil
+}
+
+func (r *InMemoryConnectionRepository) RefreshTtl(id string) (Connection, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	c, ok := r.connections[id]
+	if !ok {
+		return Connection{}, &EntityDoesNotExistError{
+			entityType: "connection",
+			id:         id,
+		}
+	}
+	c.Ttl = generateUnixTimestampIn20Minutes()
+	r.connections[id] = c
+
+	return c, nil
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+type InMemoryGameRepository struct {
+	mutex sync.Mutex
+	games map[string]game.Game
+}
+
+func NewInMemoryGameRepository() *InMemoryGameRepository {
+	return &InMemoryGameRepository{
+		games: map[string]game.Game{},
+	}
+}
+
+func (r *InMemoryGameRepository) CreateGame(g game.Game) (game.Game, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	g.Id = uuid.New().String()
+	g.Version = 1
+	r.games[g.Id] = *g.Clone()
+
+	return g, nil
+}
+
+func (r *InMemoryGameRepository) GetGame(id string) (game.Game, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	g, ok := r.games[id]
+	if !ok {
+		return game.Game{}, &EntityDoesNotExistError{
+			entityType: "game",
+			id:         id,
+		}
+	}
+
+	return *g.Clone(), nil
+}
+
+func (r *InMemoryGameRepository) UpdateGame(g game.Game) (game.Game, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	storedGame, ok := r.games[g.Id]
+	if !ok || storedGame.Version != g.Version {
+		return game.Game{}, &ConcurrentModificationError{
+			entityType: "game",
+			id:         g.Id,
+		}
+	}
+
+	g.Version++
+	r.games[g.Id] = *g.Clone()
+
+	return g, nil
+}
+
+func (r *InMemoryGameRepository) ListGamesForPlayer(playerId string) ([]game.Game, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	games := []game.Game{}
+	for _, g := range r.games {
+		if g.IsPlayer(playerId) {
+			games = append(games, *g.Clone())
+		}
+	}
+
+	return games, nil
+}
+
+func (r *InMemoryGameRepository) ListGamesOutOfTime(at time.Time) ([]game.Game, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	games := []game.Game{}
+	for _, g := range r.games {
+		if g.Status == game.InProgress && g.Deadline != 0 && g.Deadline <= at.UnixNano() {
+			games = append(games, *g.Clone())
+		}
+	}
+
+	return games, nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"sort"
+	"sync"
+)
+
+type InMemoryInvitationRepository struct {
+	mutex       sync.Mutex
+	invitations map[string]Invitation
+}
+
+func NewInMemoryInvitationRepository() *InMemoryInvitationRepository {
+	return &InMemoryInvitationRepository{
+		invitations: map[string]Invitation{},
+	}
+}
+
+func (r *InMemoryInvitationRepository) CreateInvitation(playerId string, displayName string, settings game.Settings) (Invitation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	invitation := newInvitation(playerId, displayName, settings)
+	for _, ok := r.invitations[invitation.Id]; ok; _, ok = r.invitations[invitation.Id] {
+		invitation.Id = newJoinCode()
+	}
+	r.invitations[invitation.Id] = invitation
+
+	return invitation, nil
+}
+
+func (r *InMemoryInvitationRepository) GetInvitation(code string) (Invitation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.get(normaliseJoinCode(code))
+}
+
+func (r *InMemoryInvitationRepository) RedeemInvitation(code string) (Invitation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	code = normaliseJoinCode(code)
+	invitation, err := r.get(code)
+	if err != nil {
+		return Invitation{}, err
+	}
+	delete(r.invitations, code)
+
+	return invitation, nil
+}
+
+func (r *InMemoryInvitationRepository) ListPublicInvitations() ([]Invitation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	invitations := []Invitation{}
+	for _, invitation := range r.invitations {
+		if invitation.Visibility == public && !invitation.isExpired() {
+			invitations = append(invitations, invitation)
+		}
+	}
+
+	sort.Slice(invitations, func(i, j int) bool {
+		if invitations[i].Ttl != invitations[j].Ttl {
+			return invitations[i].Ttl > invitations[j].Ttl
+		}
+		return invitations[i].Id < invitations[j].Id
+	})
+	if len(invitations) > publicInvitationLimit {
+		invitations = invitations[:publicInvitationLimit]
+	}
+
+	return invitations, nil
+}
+
+func (r *InMemoryInvitationRepository) get(code string) (Invitation, error) {
+	invitation, ok := r.invitations[code]
+	if !ok || invitation.isExpired() {
+		return Invitation{}, &EntityDoesNotExistError{
+			entityType: "invitation",
+			id:         code,
+		}
+	}
+
+	return invitation, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"sync"
+)
+
+type InMemoryMatchQueueRepository struct {
+	mutex   sync.Mutex
+	entries []QueueEntry
+}
+
+func NewInMemoryMatchQueueRepository() *InMemoryMatchQueueRepository {
+	return &InMemoryMatchQueueRepository{}
+}
+
+func (r *InMemoryMatchQueueRepository) Enqueue(id string, gameType string) (QueueEntry, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.remove(id)
+	entry := newQueueEntry(id, gameType)
+	r.entries = append(r.entries, entry)
+
+	return entry, nil
+}
+
+func (r *InMemoryMatchQueueRepository) Dequeue(gameType string, excludeId string, queuedBefore int64) (QueueEntry, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, entry := range r.entries {
+		if entry.GameType == gameType && entry.Id != excludeId && entry.QueuedAt < queuedBefore {
+			r.remove(entry.Id)
+			return entry, nil
+		}
+	}
+
+	return QueueEntry{}, &EntityDoesNotExistError{
+		entityType: "match queue",
+		id:         gameType,
+	}
+}
+
+func (r *InMemoryMatchQueueRepository) Remove(id string) (QueueEntry, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry, ok := r.remove(id)
+	if !ok {
+		return QueueEntry{}, &EntityDoesNotExistError{
+			entityType: "match queue",
+			id:         id,
+		}
+	}
+
+	return entry, nil
+}
+
+func (r *InMemoryMatchQueueRepository) remove(id string) (QueueEntry, bool) {
+	for i, entry := range r.entries {
+		if entry.Id == id {
+			r.entries = append(r.entries[:i], r.entries[i+1:]...)
+			return entry, true
+		}
+	}
+	return QueueEntry{}, false
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type InMemoryPlayerRepository struct {
+	mutex   sync.Mutex
+	players map[string]Player
+}
+
+func NewInMemoryPlayerRepository() *InMemoryPlayerRepository {
+	return &InMemoryPlayerRepository{
+		players: map[string]Player{},
+	}
+}
+
+func (r *InMemoryPlayerRepository) GetPlayer(id string) (Player, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p, ok := r.players[id]
+	if !ok {
+		return Player{}, &EntityDoesNotExistError{
+			entityType: "player",
+			id:         id,
+		}
+	}
+
+	return p, nil
+}
+
+func (r *InMemoryPlayerRepository) ConnectPlayer(id string, connectionId string, displayName string) (Player, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p := r.players[id]
+	p.Id = id
+	p.ConnectionId = connectionId
+	p.DisplayName = displayName
+	p.Presence = online
+	p.ConnectedAt = time.Now().UnixNano()
+	r.players[id] = p
+
+	return p, nil
+}
+
+func (r *InMemoryPlayerRepository) DisconnectPlayer(id string, connectionId string) (Player, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	p, ok := r.players[id]
+	if !ok {
+		return Player{}, &EntityDoesNotExistError{
+			entityType: "player",
+			id:         id,
+		}
+	}
+
+	if p.ConnectionId == connectionId {
+		p.ConnectionId = ""
+		p.Presence = ""
+		p.ConnectedAt = 0
+		r.players[id] = p
+	}
+
+	return p, nil
+}
+
+func (r *InMemoryPlayerRepository) ListOnlinePlayers() ([]Player, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	players := []Player{}
+	since := onlineSince()
+	for _, p := range r.players {
+		if p.Presence == online && p.ConnectedAt > since {
+			players = append(players, p)
+		}
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].ConnectedAt != players[j].ConnectedAt {
+			return players[i].ConnectedAt > players[j].ConnectedAt
+		}
+		return players[i].Id < players[j].Id
+	})
+
+	return players, nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"sort"
+	"sync"
+)
+
+type InMemorySpectatorRepository struct {
+	mutex      sync.Mutex
+	spectators map[string]Spectator
+}
+
+func NewInMemorySpectatorRepository() *InMemorySpectatorRepository {
+	return &InMemorySpectatorRepository{
+		spectators: map[string]Spectator{},
+	}
+}
+
+func (r *InMemorySpectatorRepository) AddSpectator(gameId string, connectionId string) (Spectator, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	s := newSpectator(gameId, connectionId)
+	r.spectators[s.Id] = s
+
+	return s, nil
+}
+
+func (r *InMemorySpectatorRepository) ListSpectators(gameId string) ([]Spectator, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	spectators := []Spectator{}
+	for _, s := range r.spectators {
+		if s.GameId == gameId {
+			spectators = append(spectators, s)
+		}
+	}
+	sort.Slice(spectators, func(i, j int) bool {
+		return spectators[i].Id < spectators[j].Id
+	})
+
+	return spectators, nil
+}
+
+func (r *InMemorySpectatorRepository) RemoveSpectatorsForConnection(connectionId string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, s := range r.spectators {
+		if s.ConnectionId == connectionId {
+			delete(r.spectators, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Invitation is an open game waiting for an opponent, who joins it with the invitation's ID as a join code
+type Invitation struct {
+	Id          string
+	PlayerId    string
+	DisplayName string
+	Settings    game.Settings
+	// Visibility is only set for public games, so that only their invitations are in the visibility index and listed
+	// in the lobby
+	Visibility string `dynamodbav:",omitempty"`
+	Ttl        int64
+}
+
+var invitationTableName = os.Getenv("INVITATION_TABLE_NAME")
+
+const (
+	// joinCodeAlphabet leaves out letters and numbers that are easily mistaken for each other, such as O and 0
+	joinCodeAlphabet          = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	joinCodeLength            = 6
+	invitationLifetimeMinutes = 60
+	// maxJoinCodeAttempts is how many codes to try before giving up, in case a new code is already in use
+	maxJoinCodeAttempts = 5
+
+	invitationVisibilityIndexName = "Visibility-Ttl-index"
+	public                        = "PUBLIC"
+	// publicInvitationLimit is how many public invitations are listed, newest first
+	publicInvitationLimit = 50
+)
+
+type InvitationRepository interface {
+	CreateInvitation(playerId string, displayName string, settings game.Settings) (Invitation, error)
+	GetInvitation(code string) (Invitation, error)
+	// RedeemInvitation removes and returns the invitation, so that only one player can join with its code
+	RedeemInvitation(code string) (Invitation, error)
+	ListPublicInvitations() ([]Invitation, error)
+}
+
+type DynamoInvitationRepository struct {
+	svc       dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+func NewDynamoInvitationRepository(svc dynamodbiface.DynamoDBAPI) *DynamoInvitationRepository {
+	return &DynamoInvitationRepository{
+		svc:       svc,
+		tableName: invitationTableName,
+	}
+}
+
+var newJoinCode = func() string {
+	code := make([]byte, joinCodeLength)
+	for i := range code {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(joinCodeAlphabet))))
+		if err != nil {
+			panic(err)
+		}
+		code[i] = joinCodeAlphabet[index.Int64()]
+	}
+	return string(code)
+}
+
+// normaliseJoinCode lets players type codes in lower case and with spaces or dashes between the characters
+func normaliseJoinCode(code string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToUpper(code))
+}
+
+func newInvitation(playerId string, displayName string, settings game.Settings) Invitation {
+	invitation := Invitation{
+		Id:          newJoinCode(),
+		PlayerId:    playerId,
+		DisplayName: displayName,
+		Settings:    settings,
+		Ttl:         generateUnixTimestampInXMinutes(invitationLifetimeMinutes),
+	}
+	if settings.IsPublic {
+		invitation.Visibility = public
+	}
+	return invitation
+}
+
+// isExpired is needed as well as the table's TTL, as DynamoDB can take days to delete items once their TTL passes
+func (i Invitation) isExpired() bool {
+	return time.Now().Unix() >= i.Ttl
+}
+
+func (r *DynamoInvitationRepository) CreateInvitation(playerId string, displayName string, settings game.Settings) (Invitation, error) {
+	for attempt := 0; attempt < maxJoinCodeAttempts; attempt++ {
+		invitation := newInvitation(playerId, displayName, settings)
+
+		result, err := dynamodbattribute.MarshalMap(invitation)
+		if err != nil {
+			return Invitation{}, err
+		}
+
+		input := &dynamodb.PutItemInput{
+			TableName:           aws.String(r.tableName),
+			Item:                result,
+			ConditionExpression: aws.String("attribute_not_exists(Id)"),
+		}
+		if _, err := r.svc.PutItem(input); err == nil {
+			return invitation, nil
+		} else if !isConditionalCheckFailed(err) {
+			return Invitation{}, err
+		}
+	}
+
+	return Invitation{}, fmt.Errorf("no unused join code was found after %d attempts", maxJoinCodeAttempts)
+}
+
+func (r *DynamoInvitationRepository) GetInvitation(code string) (Invitation, error) {
+	code = normaliseJoinCode(code)
+	invitation := Invitation{}
+
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(code),
+			},
+		},
+	}
+
+	result, err := r.svc.GetItem(input)
+	if err != nil {
+		return Invitation{}, err
+	}
+	if result.Item != nil {
+		if err := dynamodbattribute.UnmarshalMap(result.Item, &invitation); err != nil {
+			return Invitation{}, err
+		}
+	}
+
+	if result.Item == nil || invitation.isExpired() {
+		return Invitation{}, &EntityDoesNotExistError{
+			entityType: r.tableName,
+			id:         code,
+		}
+	}
+	return invitation, nil
+}
+
+func (r *DynamoInvitationRepository) RedeemInvitation(code string) (Invitation, error) {
+	code = normaliseJoinCode(code)
+
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(code),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(Id) AND Ttl > :Now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Now": {
+				N: aws.String(strconv.FormatInt(time.Now().Unix(), 10)),
+			},
+		},
+		ReturnValues: aws.String("ALL_OLD"),
+	}
+
+	result, err := r.svc.DeleteItem(input)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return Invitation{}, &EntityDoesNotExistError{
+				entityType: r.tableName,
+				id:         code,
+			}
+		}
+		return Invitation{}, err
+	}
+
+	invitation := Invitation{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &invitation); err != nil {
+		return Invitation{}, err
+	}
+
+	return invitation, nil
+}
+
+func (r *DynamoInvitationRepository) ListPublicInvitations() ([]Invitation, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(invitationVisibilityIndexName),
+		KeyConditionExpression: aws.String("Visibility = :Visibility AND Ttl > :Now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Visibility": {
+				S: aws.String(public),
+			},
+			":Now": {
+				N: aws.String(strconv.FormatInt(time.Now().Unix(), 10)),
+			},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int64(publicInvitationLimit),
+	}
+
+	result, err := r.svc.Query(input)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := []Invitation{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &invitations); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSettings = game.Settings{
+	Variant:     game.Misere,
+	Rows:        4,
+	Columns:     4,
+	IsPublic:    true,
+	TimeControl: &game.TimeControl{Type: game.Total, Limit: 5 * time.Minute, Increment: 2 * time.Second},
+}
+
+func newInvitationRepositories() map[string]InvitationRepository {
+	return map[string]InvitationRepository{
+		"dynamo":    NewDynamoInvitationRepository(newFakeDynamoDB()),
+		"in memory": NewInMemoryInvitationRepository(),
+	}
+}
+
+// storeExpiredInvitation stands in for an invitation whose TTL has passed but which hasn't been deleted yet
+func storeExpiredInvitation(t *testing.T, repository InvitationRepository, code string) {
+	invitation := Invitation{Id: code, PlayerId: "creator", Visibility: public, Ttl: time.Now().Add(-time.Minute).Unix()}
+
+	switch repository := repository.(type) {
+	case *DynamoInvitationRepository:
+		item, _ := dynamodbattribute.MarshalMap(invitation)
+		repository.svc.(*fakeDynamoDB).table(aws.String(repository.tableName))[code] = item
+	case *InMemoryInvitationRepository:
+		repository.invitations[code] = invitation
+	default:
+		t.Fatalf("%T is not a known invitation repository", repository)
+	}
+}
+
+func TestInvitationRepository_CreateAndGetInvitation(t *testing.T) {
+	for name, repository := range newInvitationRepositories() {
+		t.Run(name, func(t *testing.T) {
+			invitation, err := repository.CreateInvitation("creator", "Creator", testSettings)
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, joinCodeLength, len(invitation.Id))
+			for _, character := range invitation.Id {
+				assert.Contains(t, joinCodeAlphabet, string(character))
+			}
+			assert.Equal(t, "creator", invitation.PlayerId)
+			assert.Equal(t, "Creator", invitation.DisplayName)
+			assert.Equal(t, "PUBLIC", invitation.Visibility)
+			assert.Equal(t, testSettings, invitation.Settings)
+			assert.InDelta(t, time.Now().Add(time.Hour).Unix(), invitation.Ttl, 5)
+
+			retrieved, err := repository.GetInvitation(invitation.Id)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, invitation, retrieved)
+		})
+	}
+}
+
+func TestInvitationRepository_GetInvitation_NormalisesCode(t *testing.T) {
+	for name, repository := range newInvitationRepositories() {
+		t.Run(name, func(t *testing.T) {
+			invitation, _ := repository.CreateInvitation("creator", "Creator", testSettings)
+			typed := strings.ToLower(invitation.Id[:3]) + "-" + strings.ToLower(invitation.Id[3:]) + " "
+
+			retrieved, err := repository.GetInvitation(typed)
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, invitation, retrieved)
+		})
+	}
+}
+
+func TestInvitationRepository_RedeemInvitation_IsSingleUse(t *testing.T) {
+	for name, repository := range newInvitationRepositories() {
+		t.Run(name, func(t *testing.T) {
+			invitation, _ := repository.CreateInvitation("creator", "Creator", testSettings)
+
+			redeemed, err := repository.RedeemInvitation(invitation.Id)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, invitation, redeemed)
+
+			_, err = repository.RedeemInvitation(invitation.Id)
+			assert.IsType(t, &EntityDoesNotExistError{}, err)
+
+			_, err = repository.GetInvitation(invitation.Id)
+			assert.IsType(t, &EntityDoesNotExistError{}, err)
+		})
+	}
+}
+
+func TestInvitationRepository_Expired(t *testing.T) {
+	for name, repository := range newInvitationRepositories() {
+		t.Run(name, func(t *testing.T) {
+			storeExpiredInvitation(t, repository, "EXPIRE")
+
+			_, err := repository.GetInvitation("EXPIRE")
+			assert.IsType(t, &EntityDoesNotExistError{}, err)
+
+			_, err = repository.RedeemInvitation("EXPIRE")
+			assert.IsType(t, &EntityDoesNotExistError{}, err)
+		})
+	}
+}
+
+func TestInvitationRepository_DoesNotExist(t *testing.T) {
+	for name, repository := range newInvitationRepositories() {
+		t.Run(name, func(t *testing.T) {
+			_, err := repository.GetInvitation("NOCODE")
+			assert.IsType(t, &EntityDoesNotExistError{}, err)
+
+			_, err = repository.RedeemInvitation("NOCODE")
+			assert.IsType(t, &EntityDoesNotExistError{}, err)
+		})
+	}
+}
+
+func TestInvitationRepository_CreateInvitation_CodeInUse(t *testing.T) {
+	codes := []string{"TAKEN2", "TAKEN2", "UNUSED"}
+	defer func(original func() string) {
+		newJoinCode = original
+	}(newJoinCode)
+
+	for name, repository := range newInvitationRepositories() {
+		t.Run(name, func(t *testing.T) {
+			next := 0
+			newJoinCode = func() string {
+				next++
+				return codes[next-1]
+			}
+
+			first, _ := repository.CreateInvitation("first", "First", testSettings)
+			second, err := repository.CreateInvitation("second", "Second", testSettings)
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, "TAKEN2", first.Id)
+			assert.Equal(t, "UNUSED", second.Id)
+
+			retrieved, _ := repository.GetInvitation("TAKEN2")
+			assert.Equal(t, "first", retrieved.PlayerId)
+		})
+	}
+}
+
+func TestDynamoInvitationRepository_CreateInvitation_NoUnusedCode(t *testing.T) {
+	defer func(original func() string) {
+		newJoinCode = original
+	}(newJoinCode)
+	newJoinCode = func() string {
+		return "TAKEN2"
+	}
+	repository := NewDynamoInvitationRepository(newFakeDynamoDB())
+	_, _ = repository.CreateInvitation("first", "First", testSettings)
+
+	_, err := repository.CreateInvitation("second", "Second", testSettings)
+
+	assert.NotEqual(t, nil, err)
+}
+
+func TestInMemoryInvitationRepository_ListPublicInvitations(t *testing.T) {
+	repository := NewInMemoryInvitationRepository()
+	older, _ := repository.CreateInvitation("older", "Older", testSettings)
+	older.Ttl -= 60
+	repository.invitations[older.Id] = older
+	newer, _ := repository.CreateInvitation("newer", "Newer", testSettings)
+	_, _ = repository.CreateInvitation("private", "Private", game.Settings{})
+	storeExpiredInvitation(t, repository, "EXPIRD")
+	redeemed, _ := repository.CreateInvitation("redeemed", "Redeemed", testSettings)
+	_, _ = repository.RedeemInvitation(redeemed.Id)
+
+	invitations, err := repository.ListPublicInvitations()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []Invitation{newer, older}, invitations)
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"os"
+	"strconv"
+	"time"
+)
+
+// QueueEntry is a player waiting for an opponent. GameType is the variant and its rows x columns x win length, e.g.
+// "classic 3x3x3", so that only players wanting the same game are matched
+type QueueEntry struct {
+	Id       string
+	GameType string
+	QueuedAt int64
+	Ttl      int64
+}
+
+var matchQueueTableName = os.Getenv("MATCH_QUEUE_TABLE_NAME")
+
+const matchQueueGameTypeIndexName = "GameType-QueuedAt-index"
+
+type MatchQueueRepository interface {
+	Enqueue(id string, gameType string) (QueueEntry, error)
+	// Dequeue removes and returns the longest waiting entry for the game type that was queued before queuedBefore,
+	// other than the one with ID excludeId
+	Dequeue(gameType string, excludeId string, queuedBefore int64) (QueueEntry, error)
+	Remove(id string) (QueueEntry, error)
+}
+
+type DynamoMatchQueueRepository struct {
+	svc       dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+func NewDynamoMatchQueueRepository(svc dynamodbiface.DynamoDBAPI) *DynamoMatchQueueRepository {
+	return &DynamoMatchQueueRepository{
+		svc:       svc,
+		tableName: matchQueueTableName,
+	}
+}
+
+func newQueueEntry(id string, gameType string) QueueEntry {
+	return QueueEntry{
+		Id:       id,
+		GameType: gameType,
+		QueuedAt: time.Now().UnixNano(),
+		Ttl:      generateUnixTimestampIn20Minutes(),
+	}
+}
+
+func (r *DynamoMatchQueueRepository) Enqueue(id string, gameType string) (QueueEntry, error) {
+	entry := newQueueEntry(id, gameType)
+
+	result, err := dynamodbattribute.MarshalMap(entry)
+	if err != nil {
+		return QueueEntry{}, err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      result,
+	}
+	if _, err := r.svc.PutItem(input); err != nil {
+		return QueueEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r *DynamoMatchQueueRepository) Dequeue(gameType string, excludeId string, queuedBefore int64) (QueueEntry, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(matchQueueGameTypeIndexName),
+		KeyConditionExpression: aws.String("GameType = :GameType AND QueuedAt < :QueuedBefore"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":GameType": {
+				S: aws.String(gameType),
+			},
+			":QueuedBefore": {
+				N: aws.String(strconv.FormatInt(queuedBefore, 10)),
+			},
+		},
+		ScanIndexForward: aws.Bool(true),
+		Limit:            aws.Int64(10),
+	}
+
+	result, err := r.svc.Query(input)
+	if err != nil {
+		return QueueEntry{}, err
+	}
+
+	var candidates []QueueEntry
+	if err := dynamodbattribute.UnmarshalListOfMaps(result.Items, &candidates); err != nil {
+		return QueueEntry{}, err
+	}
+
+	// Another request may be pairing with the same entries, so an entry is only ours once we have deleted it
+	for _, candidate := range candidates {
+		if candidate.Id == excludeId {
+			continue
+		}
+
+		entry, err := r.Remove(candidate.Id)
+		if err == nil {
+			return entry, nil
+		} else if !errors.Is(err, errs.ErrNotFound) {
+			return QueueEntry{}, err
+		}
+	}
+
+	return QueueEntry{}, &EntityDoesNotExistError{
+		entityType: r.tableName,
+		id:         gameType,
+	}
+}
+
+func (r *DynamoMatchQueueRepository) Remove(id string) (QueueEntry, error) {
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(id),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(Id)"),
+		ReturnValues:        aws.String("ALL_OLD"),
+	}
+
+	result, err := r.svc.DeleteItem(input)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return QueueEntry{}, &EntityDoesNotExistError{
+				entityType: r.tableName,
+				id:         id,
+			}
+		}
+		return QueueEntry{}, err
+	}
+
+	entry := QueueEntry{}
+	if err := dynamodbattribute.UnmarshalMap(result.Attributes, &entry); err != nil {
+		return QueueEntry{}, err
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInMemoryMatchQueueRepository_DequeueOldestFirst(t *testing.T) {
+	repository := NewInMemoryMatchQueueRepository()
+	_, _ = repository.Enqueue("first", "classic 3x3x3")
+	_, _ = repository.Enqueue("other", "classic 4x4x4")
+	_, _ = repository.Enqueue("second", "classic 3x3x3")
+
+	entry, err := repository.Dequeue("classic 3x3x3", "", time.Now().UnixNano())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "first", entry.Id)
+
+	entry, err = repository.Dequeue("classic 3x3x3", "", time.Now().UnixNano())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "second", entry.Id)
+
+	_, err = repository.Dequeue("classic 3x3x3", "", time.Now().UnixNano())
+	assert.Equal(t, &EntityDoesNotExistError{entityType: "match queue", id: "classic 3x3x3"}, err)
+}
+
+func TestInMemoryMatchQueueRepository_DequeueExcludesCaller(t *testing.T) {
+	repository := NewInMemoryMatchQueueRepository()
+	_, _ = repository.Enqueue("caller", "classic 3x3x3")
+
+	_, err := repository.Dequeue("classic 3x3x3", "caller", time.Now().UnixNano())
+
+	assert.Equal(t, &EntityDoesNotExistError{entityType: "match queue", id: "classic 3x3x3"}, err)
+}
+
+func TestInMemoryMatchQueueRepository_EnqueueTwiceReplacesEntry(t *testing.T) {
+	repository := NewInMemoryMatchQueueRepository()
+	_, _ = repository.Enqueue("caller", "classic 3x3x3")
+	_, _ = repository.Enqueue("caller", "classic 4x4x4")
+
+	_, err := repository.Dequeue("classic 3x3x3", "", time.Now().UnixNano())
+	assert.IsType(t, &EntityDoesNotExistError{}, err)
+
+	entry, err := repository.Dequeue("classic 4x4x4", "", time.Now().UnixNano())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "caller", entry.Id)
+}
+
+func TestInMemoryMatchQueueRepository_DequeueOnlyQueuedBefore(t *testing.T) {
+	repository := NewInMemoryMatchQueueRepository()
+	first, _ := repository.Enqueue("first", "classic 3x3x3")
+	second, _ := repository.Enqueue("second", "classic 3x3x3")
+
+	_, err := repository.Dequeue("classic 3x3x3", "first", first.QueuedAt)
+	assert.IsType(t, &EntityDoesNotExistError{}, err)
+
+	entry, err := repository.Dequeue("classic 3x3x3", "second", second.QueuedAt)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "first", entry.Id)
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Player outlives any one websocket connection. ConnectionId is the player's current connection, or empty while
+// they are offline
+type Player struct {
+	Id           string
+	ConnectionId string
+	DisplayName  string
+	// Presence and ConnectedAt are only set while the player is online, so that only online players are in the
+	// presence index
+	Presence    string `dynamodbav:",omitempty"`
+	ConnectedAt int64  `dynamodbav:",omitempty"`
+}
+
+var playerTableName = os.Getenv("PLAYER_TABLE_NAME")
+
+const (
+	playerPresenceIndexName = "Presence-ConnectedAt-index"
+	online                  = "ONLINE"
+	// maxConnectionDuration is how long API Gateway keeps a websocket connection open. A player who connected longer
+	// ago than this has been disconnected whether or not $disconnect reached us, so is no longer listed as online
+	maxConnectionDuration = 2 * time.Hour
+)
+
+type PlayerRepository interface {
+	GetPlayer(id string) (Player, error)
+	// ConnectPlayer creates the player if they have never connected before
+	ConnectPlayer(id string, connectionId string, displayName string) (Player, error)
+	// DisconnectPlayer leaves the player alone if they have since reconnected on a different connection
+	DisconnectPlayer(id string, connectionId string) (Player, error)
+	// ListOnlinePlayers lists every player who is online, most recently connected first
+	ListOnlinePlayers() ([]Player, error)
+}
+
+// onlineSince is the earliest a player still online can have connected
+func onlineSince() int64 {
+	return time.Now().Add(-maxConnectionDuration).UnixNano()
+}
+
+type DynamoPlayerRepository struct {
+	svc       dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+func NewDynamoPlayerRepository(svc dynamodbiface.DynamoDBAPI) *DynamoPlayerRepository {
+	return &DynamoPlayerRepository{
+		svc:       svc,
+		tableName: playerTableName,
+	}
+}
+
+func (r *DynamoPlayerRepository) GetPlayer(id string) (Player, error) {
+	p := Player{}
+
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(id),
+			},
+		},
+	}
+
+	if result, err := r.svc.GetItem(input); err != nil {
+		return Player{}, err
+
+	} else if result.Item == nil {
+		return Player{}, &EntityDoesNotExistError{
+			entityType: r.tableName,
+			id:         id,
+		}
+
+	} else if err := dynamodbattribute.UnmarshalMap(result.Item, &p); err != nil {
+		return Player{}, err
+
+	} else {
+		return p, nil
+	}
+}
+
+func (r *DynamoPlayerRepository) ConnectPlayer(id string, connectionId string, displayName string) (Player, error) {
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":ConnectionId": {
+				S: aws.String(connectionId),
+			},
+			":DisplayName": {
+				S: aws.String(displayName),
+			},
+			":Presence": {
+				S: aws.String(online),
+			},
+			":ConnectedAt": {
+				N: aws.String(strconv.FormatInt(time.Now().UnixNano(), 10)),
+			},
+		},
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(id),
+			},
+		},
+		ReturnValues:     aws.String("ALL_NEW"),
+		UpdateExpression: aws.String("SET ConnectionId = :ConnectionId, DisplayName = :DisplayName, Presence = :Presence, ConnectedAt = :ConnectedAt"),
+	}
+
+	updatedPlayer := Player{}
+	if result, err := r.svc.UpdateItem(input); err != nil {
+		return Player{}, err
+	} else if err := dynamodbattribute.UnmarshalMap(result.Attributes, &updatedPlayer); err != nil {
+		return Player{}, err
+	} else {
+		return updatedPlayer, nil
+	}
+}
+
+func (r *DynamoPlayerRepository) DisconnectPlayer(id string, connectionId string) (Player, error) {
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":ConnectionId": {
+				S: aws.String(connectionId),
+			},
+		},
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(id),
+			},
+		},
+		ConditionExpression: aws.String("ConnectionId = :ConnectionId"),
+		ReturnValues:        aws.String("ALL_NEW"),
+		UpdateExpression:    aws.String("REMOVE ConnectionId, Presence, ConnectedAt"),
+	}
+
+	updatedPlayer := Player{}
+	if result, err := r.svc.UpdateItem(input); err != nil {
+		if isConditionalCheckFailed(err) {
+			return r.GetPlayer(id)
+		}
+		return Player{}, err
+	} else if err := dynamodbattribute.UnmarshalMap(result.Attributes, &updatedPlayer); err != nil {
+		return Player{}, err
+	} else {
+		return updatedPlayer, nil
+	}
+}
+
+func (r *DynamoPlayerRepository) ListOnlinePlayers() ([]Player, error) {
+	players := []Player{}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(playerPresenceIndexName),
+		KeyConditionExpression: aws.String("Presence = :Presence AND ConnectedAt > :OnlineSince"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Presence": {
+				S: aws.String(online),
+			},
+			":OnlineSince": {
+				N: aws.String(strconv.FormatInt(onlineSince(), 10)),
+			},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	var unmarshalErr error
+	err := r.svc.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pagePlayers []Player
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pagePlayers); unmarshalErr != nil {
+			return false
+		}
+		players = append(players, pagePlayers...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	} else if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	return players, nil
+}
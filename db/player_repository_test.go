@@ -0,0 +1,93 @@
+package db
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestInMemoryPlayerRepository_ConnectPlayer_NewPlayer(t *testing.T) {
+	repository := NewInMemoryPlayerRepository()
+
+	p, err := repository.ConnectPlayer("player", "connection", "Player")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "player", p.Id)
+	assert.Equal(t, "connection", p.ConnectionId)
+	assert.Equal(t, "Player", p.DisplayName)
+	assert.Equal(t, "ONLINE", p.Presence)
+	assert.NotEqual(t, int64(0), p.ConnectedAt)
+
+	retrieved, err := repository.GetPlayer("player")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, p, retrieved)
+}
+
+func TestInMemoryPlayerRepository_GetPlayer_DoesNotExist(t *testing.T) {
+	repository := NewInMemoryPlayerRepository()
+
+	_, err := repository.GetPlayer("player")
+
+	assert.Equal(t, &EntityDoesNotExistError{entityType: "player", id: "player"}, err)
+}
+
+func TestInMemoryPlayerRepository_DisconnectPlayer(t *testing.T) {
+	repository := NewInMemoryPlayerRepository()
+	_, _ = repository.ConnectPlayer("player", "connection", "Player")
+
+	p, err := repository.DisconnectPlayer("player", "connection")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Player{Id: "player", DisplayName: "Player"}, p)
+}
+
+func TestInMemoryPlayerRepository_DisconnectPlayer_AlreadyReconnected(t *testing.T) {
+	repository := NewInMemoryPlayerRepository()
+	_, _ = repository.ConnectPlayer("player", "oldConnection", "Player")
+	reconnected, _ := repository.ConnectPlayer("player", "newConnection", "Player")
+
+	p, err := repository.DisconnectPlayer("player", "oldConnection")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, reconnected, p)
+}
+
+func TestInMemoryPlayerRepository_ListOnlinePlayers(t *testing.T) {
+	repository := NewInMemoryPlayerRepository()
+	_, _ = repository.ConnectPlayer("first", "firstConnection", "First")
+	_, _ = repository.ConnectPlayer("offline", "offlineConnection", "Offline")
+	_, _ = repository.ConnectPlayer("second", "secondConnection", "Second")
+	_, _ = repository.DisconnectPlayer("offline", "offlineConnection")
+
+	players, err := repository.ListOnlinePlayers()
+
+	assert.Equal(t, nil, err)
+	ids := []string{}
+	for _, p := range players {
+		ids = append(ids, p.Id)
+	}
+	assert.Equal(t, []string{"second", "first"}, ids)
+}
+
+func TestInMemoryPlayerRepository_ListOnlinePlayers_ConnectedTooLongAgo(t *testing.T) {
+	repository := NewInMemoryPlayerRepository()
+	p, _ := repository.ConnectPlayer("ghost", "ghostConnection", "Ghost")
+	// $disconnect never arrived for a connection API Gateway has since closed
+	p.ConnectedAt = time.Now().Add(-maxConnectionDuration - time.Minute).UnixNano()
+	repository.players["ghost"] = p
+	_, _ = repository.ConnectPlayer("online", "onlineConnection", "Online")
+
+	players, err := repository.ListOnlinePlayers()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(players))
+	assert.Equal(t, "online", players[0].Id)
+}
+
+func TestInMemoryPlayerRepository_ListOnlinePlayers_NoneOnline(t *testing.T) {
+	repository := NewInMemoryPlayerRepository()
+
+	players, err := repository.ListOnlinePlayers()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []Player{}, players)
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"os"
+)
+
+// Spectator subscribes a connection to the live updates of a game. It is keyed by connection rather than player, as
+// spectating stops as soon as the socket closes
+type Spectator struct {
+	Id           string
+	GameId       string
+	ConnectionId string
+}
+
+var spectatorTableName = os.Getenv("SPECTATOR_TABLE_NAME")
+
+const (
+	spectatorGameIndexName       = "GameId-index"
+	spectatorConnectionIndexName = "ConnectionId-index"
+)
+
+type SpectatorRepository interface {
+	AddSpectator(gameId string, connectionId string) (Spectator, error)
+	ListSpectators(gameId string) ([]Spectator, error)
+	RemoveSpectatorsForConnection(connectionId string) error
+}
+
+type DynamoSpectatorRepository struct {
+	svc       dynamodbiface.DynamoDBAPI
+	tableName string
+}
+
+func NewDynamoSpectatorRepository(svc dynamodbiface.DynamoDBAPI) *DynamoSpectatorRepository {
+	return &DynamoSpectatorRepository{
+		svc:       svc,
+		tableName: spectatorTableName,
+	}
+}
+
+func newSpectator(gameId string, connectionId string) Spectator {
+	return Spectator{
+		Id:           fmt.Sprintf("%s/%s", gameId, connectionId),
+		GameId:       gameId,
+		ConnectionId: connectionId,
+	}
+}
+
+func (r *DynamoSpectatorRepository) AddSpectator(gameId string, connectionId string) (Spectator, error) {
+	s := newSpectator(gameId, connectionId)
+
+	result, err := dynamodbattribute.MarshalMap(s)
+	if err != nil {
+		return Spectator{}, err
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      result,
+	}
+	if _, err := r.svc.PutItem(input); err != nil {
+		return Spectator{}, err
+	}
+
+	return s, nil
+}
+
+func (r *DynamoSpectatorRepository) ListSpectators(gameId string) ([]Spectator, error) {
+	return r.query(spectatorGameIndexName, "GameId", gameId)
+}
+
+func (r *DynamoSpectatorRepository) RemoveSpectatorsForConnection(connectionId string) error {
+	spectators, err := r.query(spectatorConnectionIndexName, "ConnectionId", connectionId)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range spectators {
+		input := &dynamodb.DeleteItemInput{
+			TableName: aws.String(r.tableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"Id": {
+					S: aws.String(s.Id),
+				},
+			},
+		}
+		if _, err := r.svc.DeleteItem(input); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *DynamoSpectatorRepository) query(indexName string, attributeName string, value string) ([]Spectator, error) {
+	spectators := []Spectator{}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(indexName),
+		KeyConditionExpression: aws.String(fmt.Sprintf("%s = :Value", attributeName)),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Value": {
+				S: aws.String(value),
+			},
+		},
+	}
+
+	var unmarshalErr error
+	err := r.svc.QueryPages(input, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageSpectators []Spectator
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageSpectators); unmarshalErr != nil {
+			return false
+		}
+		spectators = append(spectators, pageSpectators...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	} else if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+
+	return spectators, nil
+}
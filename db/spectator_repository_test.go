@@ -0,0 +1,37 @@
+package db
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestInMemorySpectatorRepository_ListSpectators(t *testing.T) {
+	repository := NewInMemorySpectatorRepository()
+	_, _ = repository.AddSpectator("game", "first")
+	_, _ = repository.AddSpectator("game", "second")
+	_, _ = repository.AddSpectator("game", "second")
+	_, _ = repository.AddSpectator("otherGame", "first")
+
+	spectators, err := repository.ListSpectators("game")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []Spectator{
+		{Id: "game/first", GameId: "game", ConnectionId: "first"},
+		{Id: "game/second", GameId: "game", ConnectionId: "second"},
+	}, spectators)
+}
+
+func TestInMemorySpectatorRepository_RemoveSpectatorsForConnection(t *testing.T) {
+	repository := NewInMemorySpectatorRepository()
+	_, _ = repository.AddSpectator("game", "first")
+	_, _ = repository.AddSpectator("otherGame", "first")
+	_, _ = repository.AddSpectator("game", "second")
+
+	err := repository.RemoveSpectatorsForConnection("first")
+
+	assert.Equal(t, nil, err)
+	spectators, _ := repository.ListSpectators("game")
+	assert.Equal(t, []Spectator{{Id: "game/second", GameId: "game", ConnectionId: "second"}}, spectators)
+	spectators, _ = repository.ListSpectators("otherGame")
+	assert.Equal(t, []Spectator{}, spectators)
+}
@@ -1,13 +1,14 @@
 package db
 
 import (
+	"errors"
 	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 )
 
-var svc = createClient()
-
 type EntityDoesNotExistError struct {
 	entityType string
 	id         string
@@ -17,7 +18,40 @@ func (e *EntityDoesNotExistError) Error() string {
 	return fmt.Sprintf("%s with ID %s does not exist", e.entityType, e.id)
 }
 
-func createClient() *dynamodb.DynamoDB {
+func (e *EntityDoesNotExistError) Code() string {
+	return "ENTITY_NOT_FOUND"
+}
+
+func (e *EntityDoesNotExistError) Is(target error) bool {
+	return target == errs.ErrNotFound
+}
+
+type ConcurrentModificationError struct {
+	entityType string
+	id         string
+}
+
+func (e *ConcurrentModificationError) Error() string {
+	return fmt.Sprintf("%s with ID %s was modified by another request", e.entityType, e.id)
+}
+
+func (e *ConcurrentModificationError) Code() string {
+	return "CONCURRENT_MODIFICATION"
+}
+
+func (e *ConcurrentModificationError) Is(target error) bool {
+	return target == errs.ErrConflict || target == errs.ErrRetryable
+}
+
+func isConditionalCheckFailed(err error) bool {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException
+	}
+	return false
+}
+
+func NewClient() *dynamodb.DynamoDB {
 	sess := session.Must(session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
 	}))
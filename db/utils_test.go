@@ -0,0 +1,35 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEntityDoesNotExistError_Is(t *testing.T) {
+	err := fmt.Errorf("wrapped - %w", &EntityDoesNotExistError{entityType: "game", id: "game-id"})
+
+	assert.True(t, errors.Is(err, errs.ErrNotFound))
+	assert.False(t, errors.Is(err, errs.ErrConflict))
+}
+
+func TestConcurrentModificationError_Is(t *testing.T) {
+	err := fmt.Errorf("wrapped - %w", &ConcurrentModificationError{entityType: "game", id: "game-id"})
+
+	assert.True(t, errors.Is(err, errs.ErrConflict))
+	assert.True(t, errors.Is(err, errs.ErrRetryable))
+	assert.False(t, errors.Is(err, errs.ErrNotFound))
+}
+
+func TestIsConditionalCheckFailed(t *testing.T) {
+	conditionalCheckFailed := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "the conditional request failed", nil)
+
+	assert.True(t, isConditionalCheckFailed(conditionalCheckFailed))
+	assert.True(t, isConditionalCheckFailed(fmt.Errorf("wrapped - %w", conditionalCheckFailed)))
+	assert.False(t, isConditionalCheckFailed(awserr.New(dynamodb.ErrCodeResourceNotFoundException, "no table", nil)))
+	assert.False(t, isConditionalCheckFailed(errors.New("plain")))
+}
@@ -0,0 +1,237 @@
+package engine
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"math/rand"
+)
+
+type Difficulty string
+
+const (
+	Random  Difficulty = "random"
+	Greedy  Difficulty = "greedy"
+	Perfect Difficulty = "perfect"
+)
+
+// PlayerId is stored in place of a connection ID for the side the computer is playing
+const PlayerId = "computer"
+
+// MaxPerfectSquares is the most squares a board can have for the perfect computer to play on it. Beyond this a full
+// search is too slow for a lambda, and a search that was cut off short would no longer be perfect
+const MaxPerfectSquares = 9
+
+const winScore = 1000
+
+type UnknownDifficultyError struct {
+	difficulty string
+}
+
+func (e *UnknownDifficultyError) Error() string {
+	return fmt.Sprintf("%s is not a valid difficulty", e.difficulty)
+}
+
+func (e *UnknownDifficultyError) Code() string {
+	return "UNKNOWN_DIFFICULTY"
+}
+
+func (e *UnknownDifficultyError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+type NoLegalMovesError struct{}
+
+func (e *NoLegalMovesError) Error() string {
+	return "there are no legal moves left to make"
+}
+
+func (e *NoLegalMovesError) Code() string {
+	return "NO_LEGAL_MOVES"
+}
+
+func (e *NoLegalMovesError) Is(target error) bool {
+	return target == errs.ErrConflict
+}
+
+type UnsupportedVariantError struct {
+	difficulty Difficulty
+	variant    game.Variant
+}
+
+func (e *UnsupportedVariantError) Error() string {
+	return fmt.Sprintf("the %s computer cannot play %s games", e.difficulty, e.variant)
+}
+
+func (e *UnsupportedVariantError) Code() string {
+	return "UNSUPPORTED_VARIANT"
+}
+
+func (e *UnsupportedVariantError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+type BoardTooLargeError struct {
+	difficulty Difficulty
+	squares    int
+}
+
+func (e *BoardTooLargeError) Error() string {
+	return fmt.Sprintf("the %s computer can only play on boards of up to %d squares, not %d", e.difficulty, MaxPerfectSquares, e.squares)
+}
+
+func (e *BoardTooLargeError) Code() string {
+	return "BOARD_TOO_LARGE"
+}
+
+func (e *BoardTooLargeError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+func ParseDifficulty(difficulty string) (Difficulty, error) {
+	switch Difficulty(difficulty) {
+	case Random, Greedy, Perfect:
+		return Difficulty(difficulty), nil
+	default:
+		return "", &UnknownDifficultyError{difficulty: difficulty}
+	}
+}
+
+// CheckVariant returns an error if the computer can't play the variant at the given difficulty. The greedy and perfect
+// searches only understand the classic rules, so the other variants can only be played against the random computer
+func CheckVariant(difficulty Difficulty, variant game.Variant) error {
+	if difficulty == Random || variant == "" || variant == game.Classic {
+		return nil
+	}
+	return &UnsupportedVariantError{
+		difficulty: difficulty,
+		variant:    variant,
+	}
+}
+
+// CheckBoardSize returns an error if the computer can't play on a board of the given size at the given difficulty. The
+// perfect computer searches every line of play to the end, so is limited to MaxPerfectSquares
+func CheckBoardSize(difficulty Difficulty, rows int, columns int) error {
+	if difficulty != Perfect || rows*columns <= MaxPerfectSquares {
+		return nil
+	}
+	return &BoardTooLargeError{
+		difficulty: difficulty,
+		squares:    rows * columns,
+	}
+}
+
+func ChooseMove(g *game.Game, difficulty Difficulty) (int, error) {
+	if err := CheckVariant(difficulty, g.Variant); err != nil {
+		return 0, err
+	}
+	if err := CheckBoardSize(difficulty, g.Rows(), g.Columns()); err != nil {
+		return 0, err
+	}
+
+	// The search plays out moves on copies of the game, which shouldn't be held to its clock
+	g = g.Clone()
+	g.TimeControl = game.TimeControl{}
+	g.Deadline = 0
+
+	moves := g.LegalMoves()
+	if len(moves) == 0 {
+		return 0, &NoLegalMovesError{}
+	}
+
+	switch difficulty {
+	case Random:
+		return chooseRandomMove(moves), nil
+	case Greedy:
+		return chooseGreedyMove(g, moves), nil
+	case Perfect:
+		return choosePerfectMove(g, moves), nil
+	default:
+		return 0, &UnknownDifficultyError{difficulty: string(difficulty)}
+	}
+}
+
+func chooseRandomMove(moves []int) int {
+	return moves[rand.Intn(len(moves))]
+}
+
+// chooseGreedyMove takes a win if one is available, otherwise blocks the opponent's win, otherwise plays randomly
+func chooseGreedyMove(g *game.Game, moves []int) int {
+	if move, ok := findWinningMove(g, moves, g.CurrentTurn); ok {
+		return move
+	}
+
+	opponent := game.X
+	if g.CurrentTurn == game.X {
+		opponent = game.O
+	}
+	if move, ok := findWinningMove(g, moves, opponent); ok {
+		return move
+	}
+
+	return chooseRandomMove(moves)
+}
+
+func findWinningMove(g *game.Game, moves []int, piece game.Piece) (int, bool) {
+	for _, move := range moves {
+		next := g.Clone()
+		next.Board[move/next.Columns()][move%next.Columns()] = piece
+		if isWinner, winner := next.IsWinner(); isWinner && winner == piece {
+			return move, true
+		}
+	}
+	return 0, false
+}
+
+func choosePerfectMove(g *game.Game, moves []int) int {
+	depth := len(moves)
+
+	bestMove, bestScore := moves[0], -winScore-1
+	alpha, beta := -winScore-1, winScore+1
+	for _, move := range moves {
+		score := -negamax(applyMove(g, move), depth-1, 1, -beta, -alpha)
+		if score > bestScore {
+			bestMove, bestScore = move, score
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+	return bestMove
+}
+
+// negamax scores a position from the point of view of the player whose turn it is, preferring quicker wins and
+// slower losses
+func negamax(g *game.Game, depth int, ply int, alpha int, beta int) int {
+	if isWinner, _ := g.IsWinner(); isWinner {
+		return -(winScore - ply)
+	}
+
+	moves := g.LegalMoves()
+	if len(moves) == 0 || depth == 0 {
+		return 0
+	}
+
+	best := -winScore - 1
+	for _, move := range moves {
+		score := -negamax(applyMove(g, move), depth-1, ply+1, -beta, -alpha)
+		if score > best {
+			best = score
+		}
+		if score > alpha {
+			alpha = score
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
+
+func applyMove(g *game.Game, move int) *game.Game {
+	next := g.Clone()
+	if err := next.MakeMove(next.CurrentPlayer(), move); err != nil {
+		panic(fmt.Sprintf("legal move %d was rejected - %s", move, err))
+	}
+	return next
+}
@@ -0,0 +1,228 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func playGame(g *game.Game, xDifficulty Difficulty, oDifficulty Difficulty) *game.Game {
+	for !g.IsFinished() {
+		difficulty := xDifficulty
+		if g.CurrentTurn == game.O {
+			difficulty = oDifficulty
+		}
+
+		move, err := ChooseMove(g, difficulty)
+		if err != nil {
+			panic(err)
+		}
+		if err := g.MakeMove(g.CurrentPlayer(), move); err != nil {
+			panic(err)
+		}
+	}
+	return g
+}
+
+func TestParseDifficulty_Success(t *testing.T) {
+	difficulty, err := ParseDifficulty("perfect")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Perfect, difficulty)
+}
+
+func TestParseDifficulty_Unknown(t *testing.T) {
+	_, err := ParseDifficulty("impossible")
+
+	assert.Equal(t, &UnknownDifficultyError{difficulty: "impossible"}, err)
+}
+
+func TestChooseMove_UnknownDifficulty(t *testing.T) {
+	g := game.NewGame("playerX", PlayerId)
+
+	_, err := ChooseMove(g, "impossible")
+
+	assert.Equal(t, &UnknownDifficultyError{difficulty: "impossible"}, err)
+}
+
+func TestChooseMove_GameFinished(t *testing.T) {
+	g := game.NewGame("playerX", PlayerId)
+	g.Board = [][]game.Piece{
+		{game.X, game.X, game.X},
+		{game.O, game.O, game.EMPTY},
+		{game.EMPTY, game.EMPTY, game.EMPTY},
+	}
+
+	_, err := ChooseMove(g, Perfect)
+
+	assert.Equal(t, &NoLegalMovesError{}, err)
+}
+
+func TestChooseMove_RandomIsLegal(t *testing.T) {
+	g := game.NewGame("playerX", PlayerId)
+	g.Board = [][]game.Piece{
+		{game.X, game.O, game.X},
+		{game.O, game.EMPTY, game.X},
+		{game.O, game.X, game.EMPTY},
+	}
+
+	for i := 0; i < 20; i++ {
+		move, err := ChooseMove(g, Random)
+
+		assert.Equal(t, nil, err)
+		assert.Contains(t, []int{4, 8}, move)
+	}
+}
+
+func TestChooseMove_UltimateRandomIsLegal(t *testing.T) {
+	g := game.NewUltimateGame("playerX", PlayerId)
+	_ = g.MakeMove("playerX", game.UltimateMove(0, 4))
+
+	for i := 0; i < 20; i++ {
+		move, err := ChooseMove(g, Random)
+
+		assert.Equal(t, nil, err)
+		assert.Contains(t, g.LegalMoves(), move)
+	}
+}
+
+func TestChooseMove_UltimateUnsupportedDifficulty(t *testing.T) {
+	g := game.NewUltimateGame("playerX", PlayerId)
+
+	_, err := ChooseMove(g, Perfect)
+
+	assert.Equal(t, &UnsupportedVariantError{difficulty: Perfect, variant: game.Ultimate}, err)
+}
+
+func TestChooseMove_GreedyTakesWin(t *testing.T) {
+	g := game.NewGame("playerX", PlayerId)
+	g.Board = [][]game.Piece{
+		{game.X, game.X, game.EMPTY},
+		{game.O, game.O, game.EMPTY},
+		{game.X, game.EMPTY, game.EMPTY},
+	}
+	g.CurrentTurn = game.O
+
+	move, err := ChooseMove(g, Greedy)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 5, move)
+}
+
+func TestChooseMove_GreedyBlocksWin(t *testing.T) {
+	g := game.NewGame("playerX", PlayerId)
+	g.Board = [][]game.Piece{
+		{game.X, game.X, game.EMPTY},
+		{game.O, game.EMPTY, game.EMPTY},
+		{game.EMPTY, game.EMPTY, game.EMPTY},
+	}
+	g.CurrentTurn = game.O
+
+	move, err := ChooseMove(g, Greedy)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, move)
+}
+
+func TestChooseMove_PerfectTakesWin(t *testing.T) {
+	g := game.NewGame("playerX", PlayerId)
+	g.Board = [][]game.Piece{
+		{game.X, game.X, game.EMPTY},
+		{game.O, game.O, game.EMPTY},
+		{game.X, game.EMPTY, game.EMPTY},
+	}
+	g.CurrentTurn = game.O
+
+	move, err := ChooseMove(g, Perfect)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 5, move)
+}
+
+func TestChooseMove_PerfectBlocksFork(t *testing.T) {
+	// X threatens to fork with either remaining corner, so O has to force play with an edge
+	g := game.NewGame("playerX", PlayerId)
+	g.Board = [][]game.Piece{
+		{game.X, game.EMPTY, game.EMPTY},
+		{game.EMPTY, game.O, game.EMPTY},
+		{game.EMPTY, game.EMPTY, game.X},
+	}
+	g.CurrentTurn = game.O
+
+	move, err := ChooseMove(g, Perfect)
+
+	assert.Equal(t, nil, err)
+	assert.Contains(t, []int{1, 3, 5, 7}, move)
+}
+
+func TestChooseMove_PerfectAgainstPerfectIsDraw(t *testing.T) {
+	g := playGame(game.NewGame("playerX", PlayerId), Perfect, Perfect)
+
+	isWinner, _ := g.IsWinner()
+	assert.Equal(t, false, isWinner)
+}
+
+func TestChooseMove_PerfectNeverLosesToRandom(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		g := playGame(game.NewGame("playerX", PlayerId), Random, Perfect)
+
+		isWinner, winner := g.IsWinner()
+		assert.False(t, isWinner && winner == game.X)
+	}
+}
+
+func TestChooseMove_PerfectLargeBoard(t *testing.T) {
+	g, _ := game.NewGameWithSize("playerX", PlayerId, 4, 4, 3)
+
+	_, err := ChooseMove(g, Perfect)
+
+	assert.Equal(t, &BoardTooLargeError{difficulty: Perfect, squares: 16}, err)
+}
+
+func TestChooseMove_GreedyLargeBoardBlocksWin(t *testing.T) {
+	g, _ := game.NewGameWithSize("playerX", PlayerId, 6, 6, 4)
+	g.Board[2][1], g.Board[2][2], g.Board[2][3] = game.X, game.X, game.X
+	g.Board[0][0], g.Board[5][5] = game.O, game.O
+	g.Board[2][4] = game.O
+	g.CurrentTurn = game.O
+
+	move, err := ChooseMove(g, Greedy)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 12, move)
+}
+
+func TestChooseMove_IgnoresClock(t *testing.T) {
+	g := game.NewGame("playerX", PlayerId)
+	_ = g.StartClock(game.TimeControl{Type: game.PerMove, Limit: time.Second})
+	g.Deadline = 1
+
+	move, err := ChooseMove(g, Perfect)
+
+	assert.Equal(t, nil, err)
+	assert.Contains(t, []int{0, 2, 4, 6, 8}, move)
+	assert.Equal(t, int64(1), g.Deadline)
+}
+
+func TestErrors_Is(t *testing.T) {
+	tests := []struct {
+		err   error
+		class error
+	}{
+		{err: &UnknownDifficultyError{}, class: errs.ErrInvalid},
+		{err: &UnsupportedVariantError{}, class: errs.ErrInvalid},
+		{err: &BoardTooLargeError{}, class: errs.ErrInvalid},
+		{err: &NoLegalMovesError{}, class: errs.ErrConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T", test.err), func(t *testing.T) {
+			assert.True(t, errors.Is(test.err, test.class))
+			assert.True(t, errors.Is(fmt.Errorf("wrapped - %w", test.err), test.class))
+		})
+	}
+}
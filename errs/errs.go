@@ -0,0 +1,21 @@
+// Package errs classifies errors by how a request that fails with them should be answered. Error types in the other
+// packages report their class through an Is method, so that they can be checked with errors.Is however deeply they
+// are wrapped, without the code checking them needing to know every type
+package errs
+
+import "errors"
+
+var (
+	// ErrInvalid is for requests that can never succeed as they were made
+	ErrInvalid = errors.New("invalid request")
+	// ErrUnauthorized is for requests from a client that hasn't proved who it is
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden is for requests from a player who isn't allowed to make them
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound is for requests for something that doesn't exist
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is for requests that can't be carried out in the current state of what they act on
+	ErrConflict = errors.New("conflict")
+	// ErrRetryable is for conflicts with another request that has since finished, so can be sent again as they are
+	ErrRetryable = errors.New("retryable")
+)
@@ -0,0 +1,203 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+)
+
+type NoDrawOfferError struct {
+	player string
+}
+
+func (e *NoDrawOfferError) Error() string {
+	return fmt.Sprintf("there is no draw offer for %s to answer", e.player)
+}
+
+func (e *NoDrawOfferError) Code() string {
+	return "NO_DRAW_OFFER"
+}
+
+func (e *NoDrawOfferError) Is(target error) bool {
+	return target == errs.ErrConflict
+}
+
+type DrawAlreadyOfferedError struct {
+	player string
+}
+
+func (e *DrawAlreadyOfferedError) Error() string {
+	return fmt.Sprintf("%s has already offered a draw", e.player)
+}
+
+func (e *DrawAlreadyOfferedError) Code() string {
+	return "DRAW_ALREADY_OFFERED"
+}
+
+func (e *DrawAlreadyOfferedError) Is(target error) bool {
+	return target == errs.ErrConflict
+}
+
+type NotFinishedError struct {
+	gameId string
+}
+
+func (e *NotFinishedError) Error() string {
+	return fmt.Sprintf("game %s has not finished yet", e.gameId)
+}
+
+func (e *NotFinishedError) Code() string {
+	return "GAME_NOT_FINISHED"
+}
+
+func (e *NotFinishedError) Is(target error) bool {
+	return target == errs.ErrConflict
+}
+
+type RematchExistsError struct {
+	gameId     string
+	nextGameId string
+}
+
+func (e *RematchExistsError) Error() string {
+	return fmt.Sprintf("game %s has already been rematched in game %s", e.gameId, e.nextGameId)
+}
+
+func (e *RematchExistsError) Code() string {
+	return "REMATCH_EXISTS"
+}
+
+func (e *RematchExistsError) Is(target error) bool {
+	return target == errs.ErrConflict
+}
+
+func (game *Game) Resign(player string) error {
+	if err := game.checkCanAct(player); err != nil {
+		return err
+	}
+
+	game.finish(Resigned, opponentOf(game.pieceOf(player)))
+	return nil
+}
+
+// OfferDraw leaves a draw offer standing until the opponent answers it or makes a move. If the opponent has already
+// offered a draw, offering one back accepts it
+func (game *Game) OfferDraw(player string) error {
+	if err := game.checkCanAct(player); err != nil {
+		return err
+	}
+
+	piece := game.pieceOf(player)
+	switch game.DrawOfferedBy {
+	case piece:
+		return &DrawAlreadyOfferedError{player: player}
+	case opponentOf(piece):
+		game.finish(DrawAgreed, "")
+	default:
+		game.DrawOfferedBy = piece
+	}
+	return nil
+}
+
+func (game *Game) AcceptDraw(player string) error {
+	if err := game.checkDrawOffer(player); err != nil {
+		return err
+	}
+
+	game.finish(DrawAgreed, "")
+	return nil
+}
+
+func (game *Game) DeclineDraw(player string) error {
+	if err := game.checkDrawOffer(player); err != nil {
+		return err
+	}
+
+	game.DrawOfferedBy = ""
+	return nil
+}
+
+// Rematch sets up a new game between the same players with the colours swapped. The new game has to be saved, and
+// its ID stored as the NextGameId of this one, by the caller
+func (game *Game) Rematch(player string) (*Game, error) {
+	if !game.IsPlayer(player) {
+		return nil, &PlayerDoesNotExistError{
+			player: player,
+			gameId: game.Id,
+		}
+	}
+	if !game.IsFinished() {
+		return nil, &NotFinishedError{gameId: game.Id}
+	}
+	if game.NextGameId != "" {
+		return nil, &RematchExistsError{
+			gameId:     game.Id,
+			nextGameId: game.NextGameId,
+		}
+	}
+
+	rematch, err := game.rules().NewGame(game.PlayerO, game.PlayerX, game.Rows(), game.Columns(), game.winLength())
+	if err != nil {
+		return nil, err
+	}
+	if game.Variant != "" {
+		rematch.Variant = game.Variant
+	}
+	rematch.ComputerDifficulty = game.ComputerDifficulty
+	rematch.IsPublic = game.IsPublic
+	rematch.HintsEnabled = game.HintsEnabled
+	rematch.PreviousGameId = game.Id
+
+	if game.HasClock() {
+		if err := rematch.StartClock(game.TimeControl); err != nil {
+			return nil, err
+		}
+	}
+
+	return rematch, nil
+}
+
+// Abandon ends a game that will never be played, such as a rematch that lost a race with another
+func (game *Game) Abandon() {
+	game.finish(Abandoned, "")
+}
+
+func (game *Game) checkCanAct(player string) error {
+	if !game.IsPlayer(player) {
+		return &PlayerDoesNotExistError{
+			player: player,
+			gameId: game.Id,
+		}
+	}
+	return game.checkInProgress()
+}
+
+func (game *Game) checkDrawOffer(player string) error {
+	if err := game.checkCanAct(player); err != nil {
+		return err
+	}
+	if game.DrawOfferedBy != opponentOf(game.pieceOf(player)) {
+		return &NoDrawOfferError{player: player}
+	}
+	return nil
+}
+
+func (game *Game) finish(status Status, winner Piece) {
+	game.Status = status
+	game.Winner = winner
+	game.DrawOfferedBy = ""
+	game.Deadline = 0
+}
+
+func (game *Game) pieceOf(player string) Piece {
+	if game.PlayerX == player {
+		return X
+	}
+	return O
+}
+
+func opponentOf(piece Piece) Piece {
+	if piece == X {
+		return O
+	}
+	return X
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGame_Resign(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.StartClock(TimeControl{Type: PerMove, Limit: time.Minute})
+
+	err := game.Resign("playerX")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Resigned, game.Status)
+	assert.Equal(t, O, game.Winner)
+	assert.Equal(t, int64(0), game.Deadline)
+	assert.Equal(t, true, game.IsFinished())
+}
+
+func TestGame_Resign_NotAPlayer(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	err := game.Resign("someOtherPlayer")
+
+	assert.Equal(t, &PlayerDoesNotExistError{player: "someOtherPlayer", gameId: game.Id}, err)
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_Resign_Finished(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.Resign("playerX")
+
+	err := game.Resign("playerO")
+
+	winner := O
+	assert.Equal(t, &FinishedError{winner: &winner}, err)
+}
+
+func TestGame_OfferDraw(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	err := game.OfferDraw("playerO")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, O, game.DrawOfferedBy)
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_OfferDraw_AlreadyOffered(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.OfferDraw("playerX")
+
+	err := game.OfferDraw("playerX")
+
+	assert.Equal(t, &DrawAlreadyOfferedError{player: "playerX"}, err)
+}
+
+func TestGame_OfferDraw_OfferedBack(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.OfferDraw("playerX")
+
+	err := game.OfferDraw("playerO")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, DrawAgreed, game.Status)
+	assert.Equal(t, Piece(""), game.DrawOfferedBy)
+}
+
+func TestGame_AcceptDraw(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.OfferDraw("playerX")
+
+	err := game.AcceptDraw("playerO")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, DrawAgreed, game.Status)
+	assert.Equal(t, Piece(""), game.Winner)
+	assert.Equal(t, &FinishedError{}, game.MakeMove("playerX", 0))
+}
+
+func TestGame_AcceptDraw_OwnOffer(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.OfferDraw("playerX")
+
+	err := game.AcceptDraw("playerX")
+
+	assert.Equal(t, &NoDrawOfferError{player: "playerX"}, err)
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_DeclineDraw(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.OfferDraw("playerX")
+
+	err := game.DeclineDraw("playerO")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Piece(""), game.DrawOfferedBy)
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_DeclineDraw_NoOffer(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	err := game.DeclineDraw("playerO")
+
+	assert.Equal(t, &NoDrawOfferError{player: "playerO"}, err)
+}
+
+func TestGame_MakeMove_DeclinesDrawOffer(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.OfferDraw("playerX")
+	_ = game.MakeMove("playerX", 4)
+	assert.Equal(t, X, game.DrawOfferedBy)
+
+	_ = game.MakeMove("playerO", 0)
+
+	assert.Equal(t, Piece(""), game.DrawOfferedBy)
+}
+
+func TestGame_Rematch(t *testing.T) {
+	game, _ := NewGameWithSize("playerX", "playerO", 4, 5, 4)
+	game.Id = "previous"
+	game.IsPublic = true
+	_ = game.StartClock(TimeControl{Type: Total, Limit: time.Minute, Increment: time.Second})
+	_ = game.Resign("playerO")
+
+	rematch, err := game.Rematch("playerO")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "playerO", rematch.PlayerX)
+	assert.Equal(t, "playerX", rematch.PlayerO)
+	assert.Equal(t, "previous", rematch.PreviousGameId)
+	assert.Equal(t, 4, rematch.Rows())
+	assert.Equal(t, 5, rematch.Columns())
+	assert.Equal(t, 4, rematch.WinLength)
+	assert.Equal(t, true, rematch.IsPublic)
+	assert.Equal(t, game.TimeControl, rematch.TimeControl)
+	assert.Equal(t, time.Minute, rematch.RemainingX)
+	assert.Equal(t, InProgress, rematch.Status)
+}
+
+func TestGame_Rematch_NotFinished(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	_, err := game.Rematch("playerX")
+
+	assert.Equal(t, &NotFinishedError{gameId: game.Id}, err)
+}
+
+func TestGame_Rematch_AlreadyRematched(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.Resign("playerX")
+	game.NextGameId = "next"
+
+	_, err := game.Rematch("playerX")
+
+	assert.Equal(t, &RematchExistsError{gameId: game.Id, nextGameId: "next"}, err)
+}
+
+func TestGame_Rematch_NotAPlayer(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.Resign("playerX")
+
+	_, err := game.Rematch("someOtherPlayer")
+
+	assert.Equal(t, &PlayerDoesNotExistError{player: "someOtherPlayer", gameId: game.Id}, err)
+}
@@ -0,0 +1,252 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"strconv"
+	"strings"
+)
+
+type Result string
+
+const (
+	Winning Result = "WIN"
+	Drawing Result = "DRAW"
+	Losing  Result = "LOSS"
+)
+
+// An exhaustive search from a position with more empty squares or legal moves than this takes too long for a lambda.
+// The legal moves only come into it for variants with several moves per square, such as numerical tic-tac-toe
+const (
+	maxAnalysisSquares = 10
+	maxAnalysisMoves   = 32
+)
+
+type HintsDisabledError struct {
+	gameId string
+}
+
+func (e *HintsDisabledError) Error() string {
+	return fmt.Sprintf("game %s was created without hints, so it can't be analysed until it has finished", e.gameId)
+}
+
+func (e *HintsDisabledError) Code() string {
+	return "HINTS_DISABLED"
+}
+
+func (e *HintsDisabledError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+type PositionTooLargeError struct {
+	emptySquares int
+	legalMoves   int
+}
+
+func (e *PositionTooLargeError) Error() string {
+	return fmt.Sprintf(
+		"a position with %d empty squares and %d legal moves is too large to analyse, the most is %d empty squares and %d legal moves",
+		e.emptySquares, e.legalMoves, maxAnalysisSquares, maxAnalysisMoves,
+	)
+}
+
+func (e *PositionTooLargeError) Code() string {
+	return "POSITION_TOO_LARGE"
+}
+
+func (e *PositionTooLargeError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+// MoveAnalysis is the result a move leads to for the player making it, assuming best play from both sides afterwards
+type MoveAnalysis struct {
+	Move   int
+	Result Result
+	// Plies is how many plies, counting this move, it takes to win or lose. Best play wins as quickly and loses as
+	// slowly as possible. It is zero for a draw
+	Plies int
+}
+
+// AnalyzeAt analyses the position after the first ply moves. In-progress games can only be analysed if they were
+// created with hints enabled
+func (game *Game) AnalyzeAt(ply int) ([]MoveAnalysis, error) {
+	if !game.IsFinished() && !game.HintsEnabled {
+		return nil, &HintsDisabledError{gameId: game.Id}
+	}
+
+	position, err := game.ReplayTo(ply)
+	if err != nil {
+		return nil, err
+	}
+	return position.Analyze()
+}
+
+// Analyze scores every legal move in the current position by searching every line of play out to the end of the game
+func (game *Game) Analyze() ([]MoveAnalysis, error) {
+	analysis := []MoveAnalysis{}
+	if game.IsFinished() {
+		return analysis, nil
+	}
+
+	rules := game.rules()
+	moves := rules.LegalMoves(game)
+	if emptySquares := len(game.emptySquares()); emptySquares > maxAnalysisSquares || len(moves) > maxAnalysisMoves {
+		return nil, &PositionTooLargeError{
+			emptySquares: emptySquares,
+			legalMoves:   len(moves),
+		}
+	}
+
+	position := game.Clone()
+	position.Moves = nil
+	a := &analyser{
+		rules:      rules,
+		symmetries: boardSymmetries(game.Rows(), game.Columns()),
+		scores:     map[string]score{},
+	}
+
+	for _, move := range moves {
+		s := a.score(a.play(position, move)).reply()
+		analysis = append(analysis, MoveAnalysis{
+			Move:   move,
+			Result: s.result,
+			Plies:  s.plies,
+		})
+	}
+	return analysis, nil
+}
+
+// score is the result of a position for the player whose turn it is
+type score struct {
+	result Result
+	plies  int
+}
+
+// reply turns the score for the player who is about to move into the score for the player who has just moved
+func (s score) reply() score {
+	switch s.result {
+	case Winning:
+		return score{result: Losing, plies: s.plies + 1}
+	case Losing:
+		return score{result: Winning, plies: s.plies + 1}
+	default:
+		return s
+	}
+}
+
+func (s score) betterThan(other score) bool {
+	return s.value() > other.value()
+}
+
+// value orders scores so that quicker wins and slower losses come first. No game lasts more than
+// maxAnalysisSquares plies from a position that can be analysed, so every win is worth more than every draw
+func (s score) value() int {
+	switch s.result {
+	case Winning:
+		return maxAnalysisSquares + 1 - s.plies
+	case Losing:
+		return -maxAnalysisSquares - 1 + s.plies
+	default:
+		return 0
+	}
+}
+
+type analyser struct {
+	rules      Rules
+	symmetries [][]int
+	// scores are memoised by the position's key, which is the same for positions that are reflections or rotations
+	// of each other
+	scores map[string]score
+}
+
+func (a *analyser) play(position *Game, move int) *Game {
+	next := position.Clone()
+	a.rules.ApplyMove(next, move)
+	next.CurrentTurn = opponentOf(next.CurrentTurn)
+	return next
+}
+
+func (a *analyser) score(position *Game) score {
+	key := a.key(position)
+	if s, ok := a.scores[key]; ok {
+		return s
+	}
+
+	var best score
+	if a.rules.IsTerminal(position) {
+		winner, _ := a.rules.Outcome(position)
+		switch winner {
+		case EMPTY:
+			best = score{result: Drawing}
+		case position.CurrentTurn:
+			best = score{result: Winning}
+		default:
+			best = score{result: Losing}
+		}
+	} else {
+		moves := a.rules.LegalMoves(position)
+		if len(moves) == 0 {
+			best = score{result: Drawing}
+		}
+		for i, move := range moves {
+			s := a.score(a.play(position, move)).reply()
+			if i == 0 || s.betterThan(best) {
+				best = s
+			}
+		}
+	}
+
+	a.scores[key] = best
+	return best
+}
+
+// key identifies a position by the smallest of its board's reflections and rotations. A board where the next move is
+// restricted to one part of it, as in Ultimate tic-tac-toe, is only the same as itself
+func (a *analyser) key(position *Game) string {
+	var builder strings.Builder
+	builder.WriteString(string(position.CurrentTurn))
+
+	if position.ActiveBoard != nil {
+		builder.WriteString(strconv.Itoa(*position.ActiveBoard))
+		builder.WriteString(boardKey(position, a.symmetries[0]))
+		return builder.String()
+	}
+
+	smallest := ""
+	for i, symmetry := range a.symmetries {
+		if key := boardKey(position, symmetry); i == 0 || key < smallest {
+			smallest = key
+		}
+	}
+	builder.WriteString(smallest)
+	return builder.String()
+}
+
+// boardKey writes out the board with each square moved to where the symmetry takes it. Every piece is a single
+// character, including the numbers of numerical tic-tac-toe
+func boardKey(position *Game, symmetry []int) string {
+	key := make([]byte, len(symmetry))
+	for square, to := range symmetry {
+		key[to] = position.pieceAt(square)[0]
+	}
+	return string(key)
+}
+
+// boardSymmetries lists where each square ends up under each of the Symmetries that take the board onto itself,
+// starting with leaving it as it is. A square board has eight, and any other board four
+func boardSymmetries(rows int, columns int) [][]int {
+	var symmetries [][]int
+	for _, symmetry := range Symmetries {
+		if rows != columns && !symmetry.keepsShape() {
+			continue
+		}
+
+		squares := make([]int, rows*columns)
+		for square := range squares {
+			r, c := symmetry.transform(square/columns, square%columns, rows, columns)
+			squares[square] = r*columns + c
+		}
+		symmetries = append(symmetries, squares)
+	}
+	return symmetries
+}
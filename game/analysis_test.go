@@ -0,0 +1,209 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGame_Analyze_EmptyBoardIsDrawn(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	analysis, err := game.Analyze()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 9, len(analysis))
+	for i, moveAnalysis := range analysis {
+		assert.Equal(t, MoveAnalysis{Move: i, Result: Drawing}, moveAnalysis)
+	}
+}
+
+func TestGame_Analyze_TakesWin(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, X, EMPTY},
+		{O, O, EMPTY},
+		{EMPTY, EMPTY, EMPTY},
+	}
+
+	analysis, _ := game.Analyze()
+
+	assert.Equal(t, []MoveAnalysis{
+		{Move: 2, Result: Winning, Plies: 1},
+		{Move: 5, Result: Drawing},
+		{Move: 6, Result: Losing, Plies: 2},
+		{Move: 7, Result: Losing, Plies: 2},
+		{Move: 8, Result: Losing, Plies: 2},
+	}, analysis)
+}
+
+func TestGame_Analyze_DistanceToMate(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, EMPTY, EMPTY},
+		{EMPTY, O, EMPTY},
+		{EMPTY, EMPTY, X},
+	}
+	game.CurrentTurn = O
+
+	analysis, _ := game.Analyze()
+
+	// O has to take an edge, since taking a corner lets X set up two ways to win at once
+	assert.Equal(t, []MoveAnalysis{
+		{Move: 1, Result: Drawing},
+		{Move: 2, Result: Losing, Plies: 4},
+		{Move: 3, Result: Drawing},
+		{Move: 5, Result: Drawing},
+		{Move: 6, Result: Losing, Plies: 4},
+		{Move: 7, Result: Drawing},
+	}, analysis)
+}
+
+func TestGame_Analyze_Finished(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.Resign("playerX")
+
+	analysis, err := game.Analyze()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []MoveAnalysis{}, analysis)
+}
+
+func TestGame_Analyze_TooManyEmptySquares(t *testing.T) {
+	game, _ := NewGameWithSize("playerX", "playerO", 4, 4, 3)
+
+	_, err := game.Analyze()
+
+	assert.Equal(t, &PositionTooLargeError{emptySquares: 16, legalMoves: 16}, err)
+}
+
+func TestGame_Analyze_TooManyLegalMoves(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+
+	_, err := game.Analyze()
+
+	assert.Equal(t, &PositionTooLargeError{emptySquares: 9, legalMoves: 45}, err)
+}
+
+func TestGame_Analyze_Variants(t *testing.T) {
+	tests := []struct {
+		variant  Variant
+		move     int
+		expected MoveAnalysis
+	}{
+		{variant: Misere, move: 4, expected: MoveAnalysis{Move: 4, Result: Drawing}},
+		{variant: Misere, move: 0, expected: MoveAnalysis{Move: 0, Result: Losing, Plies: 9}},
+		{variant: Notakto, move: 4, expected: MoveAnalysis{Move: 4, Result: Winning, Plies: 6}},
+		{variant: Wild, move: WildMove(4, X), expected: MoveAnalysis{Move: WildMove(4, X), Result: Winning, Plies: 7}},
+		{variant: Wild, move: WildMove(0, X), expected: MoveAnalysis{Move: WildMove(0, X), Result: Drawing}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.variant), func(t *testing.T) {
+			game, _ := NewVariantGame(test.variant, "playerX", "playerO", 0, 0, 0)
+
+			analysis, err := game.Analyze()
+
+			assert.Equal(t, nil, err)
+			assert.Contains(t, analysis, test.expected)
+		})
+	}
+}
+
+func TestGame_Analyze_Numerical(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+	playMoves(t, game, NumericalMove(4, 5), NumericalMove(0, 2))
+
+	analysis, err := game.Analyze()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 7*4, len(analysis))
+	// O threatens to finish 2 + 5 + 8 in the opposite corner, which X has to take with a number that can't be built on
+	assert.Contains(t, analysis, MoveAnalysis{Move: NumericalMove(8, 7), Result: Drawing})
+	assert.Contains(t, analysis, MoveAnalysis{Move: NumericalMove(8, 1), Result: Losing, Plies: 4})
+	assert.Contains(t, analysis, MoveAnalysis{Move: NumericalMove(1, 1), Result: Losing, Plies: 2})
+}
+
+func TestGame_Analyze_Ultimate(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	game.LocalStatuses = []Status{XWon, XWon, InProgress, OWon, OWon, Draw, Draw, Draw, Draw}
+	for _, board := range []int{0, 1} {
+		setLocalBoard(game, board, [9]Piece{X, X, X, O, O, X, X, O, O})
+	}
+	for _, board := range []int{3, 4} {
+		setLocalBoard(game, board, [9]Piece{O, O, O, X, X, O, O, X, X})
+	}
+	for _, board := range []int{5, 6, 7, 8} {
+		setLocalBoard(game, board, [9]Piece{X, O, X, X, O, O, O, X, X})
+	}
+	setLocalBoard(game, 2, [9]Piece{X, EMPTY, X, O, O, EMPTY, EMPTY, EMPTY, EMPTY})
+	game.ActiveBoard = intPointer(2)
+
+	analysis, err := game.Analyze()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 5, len(analysis))
+	assert.Contains(t, analysis, MoveAnalysis{Move: UltimateMove(2, 1), Result: Winning, Plies: 1})
+}
+
+func TestGame_Analyze_MemoisesSymmetricPositions(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	a := &analyser{
+		rules:      game.rules(),
+		symmetries: boardSymmetries(3, 3),
+		scores:     map[string]score{},
+	}
+
+	a.score(game)
+
+	// There are 765 positions in tic-tac-toe once reflections and rotations are taken out
+	assert.Equal(t, 765, len(a.scores))
+}
+
+func TestGame_BoardSymmetries(t *testing.T) {
+	assert.Equal(t, 8, len(boardSymmetries(3, 3)))
+	assert.Equal(t, 4, len(boardSymmetries(3, 4)))
+
+	symmetries := boardSymmetries(3, 3)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8}, symmetries[0])
+	assert.Contains(t, symmetries, []int{2, 5, 8, 1, 4, 7, 0, 3, 6})
+}
+
+func TestGame_AnalyzeAt(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	playMoves(t, game, 0, 3, 1, 4, 2)
+
+	analysis, err := game.AnalyzeAt(4)
+
+	assert.Equal(t, nil, err)
+	assert.Contains(t, analysis, MoveAnalysis{Move: 2, Result: Winning, Plies: 1})
+}
+
+func TestGame_AnalyzeAt_HintsDisabled(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Id = "game"
+	playMoves(t, game, 0, 3)
+
+	_, err := game.AnalyzeAt(2)
+
+	assert.Equal(t, &HintsDisabledError{gameId: "game"}, err)
+}
+
+func TestGame_AnalyzeAt_HintsEnabled(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.HintsEnabled = true
+	playMoves(t, game, 0, 3)
+
+	analysis, err := game.AnalyzeAt(2)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 7, len(analysis))
+}
+
+func TestGame_AnalyzeAt_InvalidPly(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.Resign("playerX")
+
+	_, err := game.AnalyzeAt(1)
+
+	assert.Equal(t, &InvalidPlyError{ply: 1, moves: 0}, err)
+}
@@ -0,0 +1,237 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"sync"
+)
+
+// A bitset has a bit for every square of the largest board, numbered from the top left
+const bitsetWords = (MaxBoardSize*MaxBoardSize + 63) / 64
+
+type bitset [bitsetWords]uint64
+
+func (b *bitset) set(square int) {
+	b[square/64] |= 1 << (square % 64)
+}
+
+func (b *bitset) clear(square int) {
+	b[square/64] &^= 1 << (square % 64)
+}
+
+func (b *bitset) has(square int) bool {
+	return b[square/64]&(1<<(square%64)) != 0
+}
+
+// containsAll reports whether every square in the mask is set, only looking at the words a board of that size uses
+func (b *bitset) containsAll(mask *bitset, words int) bool {
+	for i := 0; i < words; i++ {
+		if b[i]&mask[i] != mask[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type UnsupportedBitboardError struct {
+	variant Variant
+}
+
+func (e *UnsupportedBitboardError) Error() string {
+	return fmt.Sprintf("%s games can't be played on a bitboard", e.variant)
+}
+
+func (e *UnsupportedBitboardError) Code() string {
+	return "UNSUPPORTED_VARIANT"
+}
+
+func (e *UnsupportedBitboardError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+// lineTable holds a mask for every line that wins on a board of one size, and which of them run through each square
+type lineTable struct {
+	words   int
+	lines   []bitset
+	squares [][]int
+}
+
+type boardSize struct {
+	rows      int
+	columns   int
+	winLength int
+}
+
+var lineTables sync.Map
+
+func lineTableFor(size boardSize) *lineTable {
+	if table, ok := lineTables.Load(size); ok {
+		return table.(*lineTable)
+	}
+
+	squares := size.rows * size.columns
+	table := &lineTable{
+		words:   (squares + 63) / 64,
+		squares: make([][]int, squares),
+	}
+	for row := 0; row < size.rows; row++ {
+		for column := 0; column < size.columns; column++ {
+			for _, direction := range lineDirections {
+				endRow, endColumn := row+(size.winLength-1)*direction[0], column+(size.winLength-1)*direction[1]
+				if endRow >= size.rows || endColumn < 0 || endColumn >= size.columns {
+					continue
+				}
+
+				var line bitset
+				for i := 0; i < size.winLength; i++ {
+					square := (row+i*direction[0])*size.columns + column + i*direction[1]
+					line.set(square)
+					table.squares[square] = append(table.squares[square], len(table.lines))
+				}
+				table.lines = append(table.lines, line)
+			}
+		}
+	}
+
+	stored, _ := lineTables.LoadOrStore(size, table)
+	return stored.(*lineTable)
+}
+
+// Bitboard is a compact position of the classic game for searching through many positions quickly. Moves are made and
+// unmade in place, and only the lines through the square played are checked for a win. Moves aren't checked for
+// legality, so searches should only make moves from LegalMoves, and not carry on once the game has finished
+type Bitboard struct {
+	size   boardSize
+	lines  *lineTable
+	pieces [2]bitset
+	// turn is 0 when it is X's turn and 1 when it is O's
+	turn   int
+	plies  int
+	winner Piece
+}
+
+func NewBitboard(rows int, columns int, winLength int) (*Bitboard, error) {
+	if err := checkBoardSize(rows, columns, winLength); err != nil {
+		return nil, err
+	}
+
+	size := boardSize{
+		rows:      rows,
+		columns:   columns,
+		winLength: winLength,
+	}
+	return &Bitboard{
+		size:   size,
+		lines:  lineTableFor(size),
+		winner: EMPTY,
+	}, nil
+}
+
+// Bitboard converts a game of the classic variant to a bitboard
+func (game *Game) Bitboard() (*Bitboard, error) {
+	if game.Variant != "" && game.Variant != Classic {
+		return nil, &UnsupportedBitboardError{variant: game.Variant}
+	}
+
+	b, err := NewBitboard(game.Rows(), game.Columns(), game.winLength())
+	if err != nil {
+		return nil, err
+	}
+	for square := 0; square < game.Rows()*game.Columns(); square++ {
+		switch game.pieceAt(square) {
+		case X:
+			b.pieces[0].set(square)
+			b.plies++
+		case O:
+			b.pieces[1].set(square)
+			b.plies++
+		}
+	}
+	if game.CurrentTurn == O {
+		b.turn = 1
+	}
+	if isWinner, winner := game.isWinner(); isWinner {
+		b.winner = winner
+	}
+	return b, nil
+}
+
+// Game converts the bitboard back to a game between two players
+func (b *Bitboard) Game(playerX string, playerO string) *Game {
+	g, _ := NewGameWithSize(playerX, playerO, b.size.rows, b.size.columns, b.size.winLength)
+	for square := 0; square < b.squares(); square++ {
+		g.place(square, b.PieceAt(square))
+	}
+	g.CurrentTurn = b.CurrentTurn()
+	g.updateStatus()
+	return g
+}
+
+func (b *Bitboard) squares() int {
+	return b.size.rows * b.size.columns
+}
+
+func (b *Bitboard) PieceAt(square int) Piece {
+	switch {
+	case b.pieces[0].has(square):
+		return X
+	case b.pieces[1].has(square):
+		return O
+	default:
+		return EMPTY
+	}
+}
+
+func (b *Bitboard) CurrentTurn() Piece {
+	if b.turn == 0 {
+		return X
+	}
+	return O
+}
+
+// Winner is the piece that has a line, or EMPTY if neither has
+func (b *Bitboard) Winner() Piece {
+	return b.winner
+}
+
+func (b *Bitboard) IsFinished() bool {
+	return b.winner != EMPTY || b.plies == b.squares()
+}
+
+// LegalMoves appends the empty squares to moves, so a search can reuse one slice per depth instead of allocating
+func (b *Bitboard) LegalMoves(moves []int) []int {
+	if b.IsFinished() {
+		return moves
+	}
+	for square := 0; square < b.squares(); square++ {
+		if !b.pieces[0].has(square) && !b.pieces[1].has(square) {
+			moves = append(moves, square)
+		}
+	}
+	return moves
+}
+
+// MakeMove places the current player's piece on the square and passes the turn to the other player
+func (b *Bitboard) MakeMove(square int) {
+	pieces := &b.pieces[b.turn]
+	pieces.set(square)
+	b.plies++
+
+	for _, line := range b.lines.squares[square] {
+		if pieces.containsAll(&b.lines.lines[line], b.lines.words) {
+			b.winner = b.CurrentTurn()
+			break
+		}
+	}
+
+	b.turn ^= 1
+}
+
+// UnmakeMove takes back the last move, which was made on the square
+func (b *Bitboard) UnmakeMove(square int) {
+	b.turn ^= 1
+	b.pieces[b.turn].clear(square)
+	b.plies--
+	// The game stops as soon as there is a winner, so the move being taken back is the one that won
+	b.winner = EMPTY
+}
@@ -0,0 +1,198 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestBitboard_NewBitboard_InvalidBoardSize(t *testing.T) {
+	_, err := NewBitboard(2, 3, 3)
+
+	assert.Equal(t, &InvalidBoardSizeError{rows: 2, columns: 3, winLength: 3}, err)
+}
+
+func TestBitboard_MakeMove(t *testing.T) {
+	b, _ := NewBitboard(3, 3, 3)
+
+	b.MakeMove(4)
+
+	assert.Equal(t, X, b.PieceAt(4))
+	assert.Equal(t, O, b.CurrentTurn())
+	assert.Equal(t, EMPTY, b.Winner())
+	assert.Equal(t, []int{0, 1, 2, 3, 5, 6, 7, 8}, b.LegalMoves(nil))
+}
+
+func TestBitboard_UnmakeMove(t *testing.T) {
+	b, _ := NewBitboard(3, 3, 3)
+	for _, square := range []int{0, 3, 1, 4} {
+		b.MakeMove(square)
+	}
+	before := *b
+
+	b.MakeMove(2)
+	assert.Equal(t, X, b.Winner())
+	b.UnmakeMove(2)
+
+	assert.Equal(t, before, *b)
+}
+
+func TestBitboard_Winner(t *testing.T) {
+	tests := []struct {
+		name                     string
+		rows, columns, winLength int
+		moves                    []int
+		expected                 Piece
+	}{
+		{name: "row", rows: 3, columns: 3, winLength: 3, moves: []int{0, 3, 1, 4, 2}, expected: X},
+		{name: "column", rows: 3, columns: 3, winLength: 3, moves: []int{0, 1, 3, 2, 6}, expected: X},
+		{name: "diagonal", rows: 3, columns: 3, winLength: 3, moves: []int{1, 0, 2, 4, 3, 8}, expected: O},
+		{name: "anti-diagonal", rows: 3, columns: 3, winLength: 3, moves: []int{2, 0, 4, 1, 6}, expected: X},
+		{name: "not enough in a row", rows: 4, columns: 4, winLength: 4, moves: []int{0, 4, 1, 5, 2}, expected: EMPTY},
+		{name: "wrapping around the edge", rows: 4, columns: 4, winLength: 3, moves: []int{2, 8, 3, 9, 4}, expected: EMPTY},
+		{name: "across words", rows: 19, columns: 19, winLength: 5, moves: []int{60, 0, 61, 1, 62, 2, 63, 3, 64}, expected: X},
+		{name: "rectangle", rows: 3, columns: 5, winLength: 4, moves: []int{5, 0, 6, 1, 7, 2, 8}, expected: X},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, _ := NewBitboard(test.rows, test.columns, test.winLength)
+
+			for _, square := range test.moves {
+				b.MakeMove(square)
+			}
+
+			assert.Equal(t, test.expected, b.Winner())
+			assert.Equal(t, test.expected != EMPTY, b.IsFinished())
+		})
+	}
+}
+
+func TestBitboard_FullBoard(t *testing.T) {
+	b, _ := NewBitboard(3, 3, 3)
+
+	for _, square := range []int{4, 0, 2, 6, 3, 5, 1, 7, 8} {
+		b.MakeMove(square)
+	}
+
+	assert.Equal(t, EMPTY, b.Winner())
+	assert.Equal(t, true, b.IsFinished())
+	assert.Equal(t, []int(nil), b.LegalMoves(nil))
+}
+
+func TestBitboard_ConvertsGame(t *testing.T) {
+	g, _ := NewGameWithSize("playerX", "playerO", 4, 5, 3)
+	playMoves(t, g, 0, 7, 12, 19, 6)
+
+	b, err := g.Bitboard()
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, O, b.CurrentTurn())
+	assert.Equal(t, X, b.PieceAt(6))
+	assert.Equal(t, O, b.PieceAt(19))
+	assert.Equal(t, EMPTY, b.PieceAt(1))
+
+	converted := b.Game("playerX", "playerO")
+	assert.Equal(t, g.Board, converted.Board)
+	assert.Equal(t, g.CurrentTurn, converted.CurrentTurn)
+	assert.Equal(t, g.Status, converted.Status)
+	assert.Equal(t, g.WinLength, converted.WinLength)
+}
+
+func TestBitboard_ConvertsFinishedGame(t *testing.T) {
+	g := NewGame("playerX", "playerO")
+	playMoves(t, g, 0, 3, 1, 4, 2)
+
+	b, _ := g.Bitboard()
+
+	assert.Equal(t, X, b.Winner())
+	assert.Equal(t, true, b.IsFinished())
+
+	converted := b.Game("playerX", "playerO")
+	assert.Equal(t, XWon, converted.Status)
+	assert.Equal(t, []int{0, 1, 2}, converted.WinningLine)
+}
+
+func TestBitboard_UnsupportedVariant(t *testing.T) {
+	g, _ := NewVariantGame(Wild, "playerX", "playerO", 0, 0, 0)
+
+	_, err := g.Bitboard()
+
+	assert.Equal(t, &UnsupportedBitboardError{variant: Wild}, err)
+}
+
+func TestBitboard_MatchesGame(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	sizes := []boardSize{{3, 3, 3}, {4, 4, 3}, {5, 7, 4}, {9, 9, 5}, {19, 19, 5}}
+
+	for _, size := range sizes {
+		for i := 0; i < 20; i++ {
+			g, _ := NewGameWithSize("playerX", "playerO", size.rows, size.columns, size.winLength)
+			b, _ := NewBitboard(size.rows, size.columns, size.winLength)
+
+			for !g.IsFinished() {
+				moves := g.LegalMoves()
+				assert.Equal(t, moves, b.LegalMoves(nil))
+
+				move := moves[random.Intn(len(moves))]
+				_ = g.MakeMove(g.CurrentPlayer(), move)
+				b.MakeMove(move)
+
+				_, winner := g.IsWinner()
+				assert.Equal(t, winner, b.Winner())
+				assert.Equal(t, g.IsFinished(), b.IsFinished())
+			}
+			assert.Equal(t, true, b.IsFinished())
+		}
+	}
+}
+
+// countGames plays out every game from the position, reusing a slice of moves for each depth
+func countGames(b *Bitboard, moves [][]int, depth int) int {
+	if b.IsFinished() {
+		return 1
+	}
+
+	games := 0
+	moves[depth] = b.LegalMoves(moves[depth][:0])
+	for _, square := range moves[depth] {
+		b.MakeMove(square)
+		games += countGames(b, moves, depth+1)
+		b.UnmakeMove(square)
+	}
+	return games
+}
+
+func countGamesWithGame(g *Game) int {
+	if g.IsFinished() {
+		return 1
+	}
+
+	games := 0
+	for _, square := range g.LegalMoves() {
+		next := g.Clone()
+		_ = next.MakeMove(next.CurrentPlayer(), square)
+		games += countGamesWithGame(next)
+	}
+	return games
+}
+
+func TestBitboard_CountsGames(t *testing.T) {
+	b, _ := NewBitboard(3, 3, 3)
+
+	assert.Equal(t, 255168, countGames(b, make([][]int, 10), 0))
+}
+
+func BenchmarkEnumerateGames_Game(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		countGamesWithGame(NewGame("playerX", "playerO"))
+	}
+}
+
+func BenchmarkEnumerateGames_Bitboard(b *testing.B) {
+	moves := make([][]int, 10)
+	for i := 0; i < b.N; i++ {
+		board, _ := NewBitboard(3, 3, 3)
+		countGames(board, moves, 0)
+	}
+}
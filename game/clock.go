@@ -0,0 +1,131 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"time"
+)
+
+type TimeControlType string
+
+const (
+	PerMove TimeControlType = "PER_MOVE"
+	Total   TimeControlType = "TOTAL"
+)
+
+const MaxTimeLimit = 24 * time.Hour
+
+// TimeControl is chosen when a game is created. PerMove gives each move Limit to be played, whereas Total gives each
+// player Limit for the whole game with Increment added back after each of their moves
+type TimeControl struct {
+	Type      TimeControlType
+	Limit     time.Duration
+	Increment time.Duration
+}
+
+type InvalidTimeControlError struct {
+	timeControl TimeControl
+}
+
+func (e *InvalidTimeControlError) Error() string {
+	return fmt.Sprintf("%s with a limit of %s and an increment of %s is not a valid time control", e.timeControl.Type, e.timeControl.Limit, e.timeControl.Increment)
+}
+
+func (e *InvalidTimeControlError) Code() string {
+	return "INVALID_TIME_CONTROL"
+}
+
+func (e *InvalidTimeControlError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+type OutOfTimeError struct {
+	player string
+}
+
+func (e *OutOfTimeError) Error() string {
+	return fmt.Sprintf("%s has run out of time", e.player)
+}
+
+func (e *OutOfTimeError) Code() string {
+	return "OUT_OF_TIME"
+}
+
+func (e *OutOfTimeError) Is(target error) bool {
+	return target == errs.ErrConflict
+}
+
+// StartClock sets the game's time control and starts X's clock
+func (game *Game) StartClock(timeControl TimeControl) error {
+	isValid := timeControl.Limit > 0 && timeControl.Limit <= MaxTimeLimit
+	switch timeControl.Type {
+	case PerMove:
+		isValid = isValid && timeControl.Increment == 0
+	case Total:
+		isValid = isValid && timeControl.Increment >= 0 && timeControl.Increment <= MaxTimeLimit
+	default:
+		isValid = false
+	}
+	if !isValid {
+		return &InvalidTimeControlError{timeControl: timeControl}
+	}
+
+	game.TimeControl = timeControl
+	game.RemainingX = timeControl.Limit
+	game.RemainingO = timeControl.Limit
+	game.Deadline = now().Add(timeControl.Limit).UnixNano()
+
+	return nil
+}
+
+func (game *Game) HasClock() bool {
+	return game.TimeControl.Type != ""
+}
+
+// IsOutOfTime reports whether the player whose turn it is has let their clock run out
+func (game *Game) IsOutOfTime() bool {
+	return game.Deadline != 0 && !now().Before(time.Unix(0, game.Deadline))
+}
+
+// ExpireClock ends the game as a loss for the player whose clock has run out. It returns false, leaving the game
+// untouched, if they still have time
+func (game *Game) ExpireClock() bool {
+	if game.IsFinished() || !game.IsOutOfTime() {
+		return false
+	}
+
+	if game.CurrentTurn == X {
+		game.RemainingX = 0
+	} else {
+		game.RemainingO = 0
+	}
+	game.finish(TimedOut, opponentOf(game.CurrentTurn))
+
+	return true
+}
+
+// updateClock is called once a move by piece has been played and the turn has passed to the other player
+func (game *Game) updateClock(piece Piece, movedAt time.Time) {
+	if !game.HasClock() {
+		return
+	}
+
+	remaining := game.TimeControl.Limit
+	if game.TimeControl.Type == Total {
+		remaining = time.Unix(0, game.Deadline).Sub(movedAt) + game.TimeControl.Increment
+	}
+
+	if piece == X {
+		game.RemainingX = remaining
+	} else {
+		game.RemainingO = remaining
+	}
+
+	if game.IsFinished() {
+		game.Deadline = 0
+	} else if game.CurrentTurn == X {
+		game.Deadline = movedAt.Add(game.RemainingX).UnixNano()
+	} else {
+		game.Deadline = movedAt.Add(game.RemainingO).UnixNano()
+	}
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func setNow(t *testing.T, at time.Time) {
+	now = func() time.Time {
+		return at
+	}
+	t.Cleanup(func() {
+		now = func() time.Time {
+			return testTime
+		}
+	})
+}
+
+func TestGame_StartClock_Total(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	err := game.StartClock(TimeControl{Type: Total, Limit: 5 * time.Minute, Increment: 2 * time.Second})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 5*time.Minute, game.RemainingX)
+	assert.Equal(t, 5*time.Minute, game.RemainingO)
+	assert.Equal(t, testTime.Add(5*time.Minute).UnixNano(), game.Deadline)
+}
+
+func TestGame_StartClock_Invalid(t *testing.T) {
+	timeControls := []TimeControl{
+		{Type: PerMove, Limit: 0},
+		{Type: PerMove, Limit: time.Minute, Increment: time.Second},
+		{Type: Total, Limit: time.Minute, Increment: -time.Second},
+		{Type: Total, Limit: 2 * MaxTimeLimit},
+		{Type: "HOURGLASS", Limit: time.Minute},
+	}
+
+	for _, timeControl := range timeControls {
+		game := NewGame("playerX", "playerO")
+
+		err := game.StartClock(timeControl)
+
+		assert.Equal(t, &InvalidTimeControlError{timeControl: timeControl}, err)
+		assert.Equal(t, false, game.HasClock())
+		assert.Equal(t, int64(0), game.Deadline)
+	}
+}
+
+func TestGame_MakeMove_PerMoveClock(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.StartClock(TimeControl{Type: PerMove, Limit: 30 * time.Second})
+	setNow(t, testTime.Add(20*time.Second))
+
+	err := game.MakeMove("playerX", 4)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 30*time.Second, game.RemainingX)
+	assert.Equal(t, 30*time.Second, game.RemainingO)
+	assert.Equal(t, testTime.Add(50*time.Second).UnixNano(), game.Deadline)
+}
+
+func TestGame_MakeMove_TotalClockWithIncrement(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.StartClock(TimeControl{Type: Total, Limit: time.Minute, Increment: 5 * time.Second})
+	setNow(t, testTime.Add(20*time.Second))
+	_ = game.MakeMove("playerX", 4)
+	setNow(t, testTime.Add(30*time.Second))
+
+	err := game.MakeMove("playerO", 0)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 45*time.Second, game.RemainingX)
+	assert.Equal(t, 55*time.Second, game.RemainingO)
+	assert.Equal(t, testTime.Add(75*time.Second).UnixNano(), game.Deadline)
+}
+
+func TestGame_MakeMove_OutOfTime(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.StartClock(TimeControl{Type: PerMove, Limit: 30 * time.Second})
+	setNow(t, testTime.Add(30*time.Second))
+
+	err := game.MakeMove("playerX", 4)
+
+	assert.Equal(t, &OutOfTimeError{player: "playerX"}, err)
+	assert.Equal(t, EMPTY, game.Board[1][1])
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_MakeMove_StopsClockWhenFinished(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.StartClock(TimeControl{Type: PerMove, Limit: 30 * time.Second})
+	game.Board = [][]Piece{
+		{X, X, EMPTY},
+		{O, O, EMPTY},
+		{EMPTY, EMPTY, EMPTY},
+	}
+
+	_ = game.MakeMove("playerX", 2)
+
+	assert.Equal(t, XWon, game.Status)
+	assert.Equal(t, int64(0), game.Deadline)
+}
+
+func TestGame_ExpireClock(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.StartClock(TimeControl{Type: Total, Limit: time.Minute})
+	_ = game.MakeMove("playerX", 4)
+	setNow(t, testTime.Add(time.Minute))
+
+	expired := game.ExpireClock()
+
+	assert.Equal(t, true, expired)
+	assert.Equal(t, TimedOut, game.Status)
+	assert.Equal(t, X, game.Winner)
+	assert.Equal(t, time.Duration(0), game.RemainingO)
+	assert.Equal(t, int64(0), game.Deadline)
+	assert.Equal(t, &FinishedError{winner: &game.Winner}, game.MakeMove("playerO", 0))
+}
+
+func TestGame_ExpireClock_TimeRemaining(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.StartClock(TimeControl{Type: Total, Limit: time.Minute})
+	setNow(t, testTime.Add(59*time.Second))
+
+	expired := game.ExpireClock()
+
+	assert.Equal(t, false, expired)
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_ExpireClock_NoClock(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	setNow(t, testTime.Add(MaxTimeLimit))
+
+	assert.Equal(t, false, game.ExpireClock())
+}
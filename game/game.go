@@ -2,6 +2,8 @@ package game
 
 import (
 	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"time"
 )
 
 type Piece string
@@ -17,6 +19,14 @@ func (e *FinishedError) Error() string {
 	return "game has ended in a draw"
 }
 
+func (e *FinishedError) Code() string {
+	return "GAME_FINISHED"
+}
+
+func (e *FinishedError) Is(target error) bool {
+	return target == errs.ErrConflict
+}
+
 type InvalidMoveError struct {
 	move int
 }
@@ -25,6 +35,14 @@ func (e *InvalidMoveError) Error() string {
 	return fmt.Sprintf("%d is not a valid move", e.move)
 }
 
+func (e *InvalidMoveError) Code() string {
+	return "INVALID_MOVE"
+}
+
+func (e *InvalidMoveError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
 type NotPlayersTurnError struct {
 	player string
 }
@@ -33,6 +51,14 @@ func (e *NotPlayersTurnError) Error() string {
 	return fmt.Sprintf("it is not %s's turn", e.player)
 }
 
+func (e *NotPlayersTurnError) Code() string {
+	return "NOT_YOUR_TURN"
+}
+
+func (e *NotPlayersTurnError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
 type PlayerDoesNotExistError struct {
 	player string
 	gameId string
@@ -42,33 +68,177 @@ func (e *PlayerDoesNotExistError) Error() string {
 	return fmt.Sprintf("user %s is not permitted to make moves in game %s", e.player, e.gameId)
 }
 
+func (e *PlayerDoesNotExistError) Code() string {
+	return "NOT_A_PLAYER"
+}
+
+func (e *PlayerDoesNotExistError) Is(target error) bool {
+	return target == errs.ErrForbidden
+}
+
+type InvalidBoardSizeError struct {
+	rows      int
+	columns   int
+	winLength int
+}
+
+func (e *InvalidBoardSizeError) Error() string {
+	return fmt.Sprintf("a %dx%d board with %d in a row to win is not a valid game", e.rows, e.columns, e.winLength)
+}
+
+func (e *InvalidBoardSizeError) Code() string {
+	return "INVALID_BOARD_SIZE"
+}
+
+func (e *InvalidBoardSizeError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+type InvalidPlyError struct {
+	ply   int
+	moves int
+}
+
+func (e *InvalidPlyError) Error() string {
+	return fmt.Sprintf("ply %d is not valid for a game with %d moves", e.ply, e.moves)
+}
+
+func (e *InvalidPlyError) Code() string {
+	return "INVALID_PLY"
+}
+
+func (e *InvalidPlyError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
 const (
 	X     Piece = "X"
 	O     Piece = "O"
 	EMPTY Piece = "_"
 )
 
+type Status string
+
+const (
+	InProgress Status = "IN_PROGRESS"
+	XWon       Status = "X_WON"
+	OWon       Status = "O_WON"
+	Draw       Status = "DRAW"
+	Resigned   Status = "RESIGNED"
+	Abandoned  Status = "ABANDONED"
+	TimedOut   Status = "TIMED_OUT"
+	DrawAgreed Status = "DRAW_AGREED"
+)
+
+const (
+	DefaultBoardSize = 3
+	DefaultWinLength = 3
+	MinBoardSize     = 3
+	MaxBoardSize     = 19
+)
+
+type Move struct {
+	Player string
+	// Move is the move as it was sent to make-move. For classic, misere and notakto games this is the square played,
+	// and for the other variants it is encoded by WildMove, NumericalMove or UltimateMove
+	Move int
+	// Square is where the piece was placed on Board, numbered from the top left, and Piece is what was placed there
+	Square    int
+	Piece     Piece
+	Timestamp time.Time
+}
+
+var now = time.Now
+
 type Game struct {
 	Id          string
 	Board       [][]Piece
+	WinLength   int
 	CurrentTurn Piece
 	PlayerX     string
 	PlayerO     string
+
+	Status      Status
+	Winner      Piece
+	WinningLine []int
+
+	ComputerDifficulty string
+	IsPublic           bool
+	// HintsEnabled lets the game be analysed while it is still being played
+	HintsEnabled bool
+
+	TimeControl TimeControl
+	RemainingX  time.Duration
+	RemainingO  time.Duration
+	// Deadline is the UnixNano time at which the current player's clock runs out, or zero when no clock is running.
+	// It is left out of DynamoDB when zero so that only running clocks are indexed
+	Deadline int64 `dynamodbav:",omitempty"`
+
+	// Variant names the Rules the game is played by
+	Variant Variant
+	// ActiveBoard is the local board the current player has to play in for Ultimate tic-tac-toe, or nil if they may
+	// play in any
+	ActiveBoard   *int
+	LocalStatuses []Status
+
+	// DrawOfferedBy is the piece of the player with a draw offer standing, if there is one
+	DrawOfferedBy  Piece
+	PreviousGameId string
+	NextGameId     string
+
+	Moves []Move
+
+	Version int
 }
 
 func NewGame(playerX string, playerO string) *Game {
 	return &Game{
-		Board: [][]Piece{
-			{EMPTY, EMPTY, EMPTY},
-			{EMPTY, EMPTY, EMPTY},
-			{EMPTY, EMPTY, EMPTY},
-		},
+		Variant:     Classic,
+		Board:       newBoard(DefaultBoardSize, DefaultBoardSize),
+		WinLength:   DefaultWinLength,
 		CurrentTurn: X,
+		Status:      InProgress,
 		PlayerX:     playerX,
 		PlayerO:     playerO,
 	}
 }
 
+func NewGameWithSize(playerX string, playerO string, rows int, columns int, winLength int) (*Game, error) {
+	if err := checkBoardSize(rows, columns, winLength); err != nil {
+		return nil, err
+	}
+
+	g := NewGame(playerX, playerO)
+	g.Board = newBoard(rows, columns)
+	g.WinLength = winLength
+
+	return g, nil
+}
+
+func checkBoardSize(rows int, columns int, winLength int) error {
+	if rows < MinBoardSize || rows > MaxBoardSize ||
+		columns < MinBoardSize || columns > MaxBoardSize ||
+		winLength < MinBoardSize || (winLength > rows && winLength > columns) {
+		return &InvalidBoardSizeError{
+			rows:      rows,
+			columns:   columns,
+			winLength: winLength,
+		}
+	}
+	return nil
+}
+
+func newBoard(rows int, columns int) [][]Piece {
+	board := make([][]Piece, rows)
+	for i := range board {
+		board[i] = make([]Piece, columns)
+		for j := range board[i] {
+			board[i][j] = EMPTY
+		}
+	}
+	return board
+}
+
 func (game *Game) MakeMove(player string, move int) error {
 	if !game.IsPlayer(player) {
 		return &PlayerDoesNotExistError{
@@ -77,11 +247,8 @@ func (game *Game) MakeMove(player string, move int) error {
 		}
 	}
 
-	if game.isDraw() {
-		return &FinishedError{}
-	}
-	if isWinner, winner := game.isWinner(); isWinner {
-		return &FinishedError{winner: &winner}
+	if err := game.checkInProgress(); err != nil {
+		return err
 	}
 
 	if !game.isValidMove(move) {
@@ -92,7 +259,25 @@ func (game *Game) MakeMove(player string, move int) error {
 		return &NotPlayersTurnError{player: player}
 	}
 
-	game.Board[move/3][move%3] = game.CurrentTurn
+	if game.IsOutOfTime() {
+		return &OutOfTimeError{player: player}
+	}
+
+	movedAt := now().UTC()
+	side := game.CurrentTurn
+	square, piece := game.rules().ApplyMove(game, move)
+	game.Moves = append(game.Moves, Move{
+		Player:    player,
+		Move:      move,
+		Square:    square,
+		Piece:     piece,
+		Timestamp: movedAt,
+	})
+
+	// Playing on instead of answering a draw offer declines it
+	if game.DrawOfferedBy != "" && game.DrawOfferedBy != side {
+		game.DrawOfferedBy = ""
+	}
 
 	if game.CurrentTurn == X {
 		game.CurrentTurn = O
@@ -100,9 +285,80 @@ func (game *Game) MakeMove(player string, move int) error {
 		game.CurrentTurn = X
 	}
 
+	game.updateStatus()
+	game.updateClock(side, movedAt)
+
 	return nil
 }
 
+func (game *Game) checkInProgress() error {
+	if game.Status != "" && game.Status != InProgress {
+		if game.Winner != "" {
+			winner := game.Winner
+			return &FinishedError{winner: &winner}
+		}
+		return &FinishedError{}
+	}
+	rules := game.rules()
+	if !rules.IsTerminal(game) {
+		return nil
+	}
+	if winner, _ := rules.Outcome(game); winner != EMPTY {
+		return &FinishedError{winner: &winner}
+	}
+	return &FinishedError{}
+}
+
+func (game *Game) updateStatus() {
+	rules := game.rules()
+	if !rules.IsTerminal(game) {
+		game.Status = InProgress
+		return
+	}
+
+	winner, line := rules.Outcome(game)
+	if winner == EMPTY {
+		game.Status = Draw
+		return
+	}
+	game.Winner = winner
+	game.WinningLine = line
+	game.Status = wonStatus(winner)
+}
+
+// ReplayTo rebuilds the game as it was after the first ply moves had been played
+func (game *Game) ReplayTo(ply int) (*Game, error) {
+	if ply < 0 || ply > len(game.Moves) {
+		return nil, &InvalidPlyError{
+			ply:   ply,
+			moves: len(game.Moves),
+		}
+	}
+
+	replay := game.Clone()
+	replay.Board = newBoard(game.Rows(), game.Columns())
+	replay.CurrentTurn = X
+	replay.Status = InProgress
+	replay.Winner = ""
+	replay.WinningLine = nil
+	replay.Moves = nil
+	replay.TimeControl = TimeControl{}
+	replay.Deadline = 0
+	replay.ActiveBoard = nil
+	if game.LocalStatuses != nil {
+		replay.LocalStatuses = newLocalStatuses()
+	}
+
+	for _, move := range game.Moves[:ply] {
+		if err := replay.MakeMove(move.Player, move.Move); err != nil {
+			return nil, err
+		}
+		replay.Moves[len(replay.Moves)-1].Timestamp = move.Timestamp
+	}
+
+	return replay, nil
+}
+
 func (game *Game) IsPlayer(player string) bool {
 	if game.PlayerX == player || game.PlayerO == player {
 		return true
@@ -110,6 +366,11 @@ func (game *Game) IsPlayer(player string) bool {
 	return false
 }
 
+// CanView reports whether the player may see the game, which anyone can do for public games
+func (game *Game) CanView(player string) bool {
+	return game.IsPublic || game.IsPlayer(player)
+}
+
 func (game *Game) GetOtherPlayer(player string) string {
 	if game.PlayerX == player {
 		return game.PlayerO
@@ -117,43 +378,114 @@ func (game *Game) GetOtherPlayer(player string) string {
 	return game.PlayerX
 }
 
-func (game *Game) isWinner() (bool, Piece) {
-	for _, row := range game.Board {
-		if areItemsInArrayEqual(row) && row[0] != EMPTY {
-			return true, row[0]
-		}
+func (game *Game) CurrentPlayer() string {
+	if game.CurrentTurn == X {
+		return game.PlayerX
 	}
+	return game.PlayerO
+}
 
-	diag1Count, diag2Count := 0, 0
-	for i := 0; i < 3; i++ {
+func (game *Game) IsWinner() (bool, Piece) {
+	return game.isWinner()
+}
 
-		count := 0
-		for j := 0; j < 3; j++ {
-			if game.Board[j][i] == game.Board[0][i] {
-				count++
-			}
-		}
+func (game *Game) IsFinished() bool {
+	if game.Status != "" && game.Status != InProgress {
+		return true
+	}
+	return game.rules().IsTerminal(game)
+}
 
-		if count >= 3 && game.Board[0][i] != EMPTY {
-			return true, game.Board[0][i]
-		}
+func (game *Game) LegalMoves() []int {
+	if game.IsFinished() {
+		return []int{}
+	}
+	return game.rules().LegalMoves(game)
+}
 
-		if game.Board[i][i] == game.Board[0][0] {
-			diag1Count++
-		}
-		if game.Board[i][2-i] == game.Board[0][2] {
-			diag2Count++
-		}
+func (game *Game) Clone() *Game {
+	clone := *game
+	clone.Board = make([][]Piece, len(game.Board))
+	for i, row := range game.Board {
+		clone.Board[i] = append([]Piece{}, row...)
+	}
+	clone.Moves = append([]Move(nil), game.Moves...)
+	clone.WinningLine = append([]int(nil), game.WinningLine...)
+	clone.LocalStatuses = append([]Status(nil), game.LocalStatuses...)
+	if game.ActiveBoard != nil {
+		activeBoard := *game.ActiveBoard
+		clone.ActiveBoard = &activeBoard
 	}
+	return &clone
+}
+
+func (game *Game) Rows() int {
+	return len(game.Board)
+}
 
-	if diag1Count >= 3 && game.Board[0][0] != EMPTY {
-		return true, game.Board[0][0]
+func (game *Game) Columns() int {
+	if len(game.Board) == 0 {
+		return 0
 	}
-	if diag2Count >= 3 && game.Board[0][2] != EMPTY {
-		return true, game.Board[0][2]
+	return len(game.Board[0])
+}
+
+// Games stored before the win length was configurable have no WinLength, so fall back to the classic rules
+func (game *Game) winLength() int {
+	if game.WinLength <= 0 {
+		return DefaultWinLength
+	}
+	return game.WinLength
+}
+
+// The directions a line can run in from its first square: right, down, down-right and down-left
+var lineDirections = [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// isWinner and isDraw ask the game's rules, so they agree with IsFinished whatever the variant
+func (game *Game) isWinner() (bool, Piece) {
+	rules := game.rules()
+	if !rules.IsTerminal(game) {
+		return false, EMPTY
+	}
+
+	winner, _ := rules.Outcome(game)
+	return winner != EMPTY, winner
+}
+
+// findWinningLine returns the squares of the first line of WinLength matching pieces on the board, or nil if there
+// is none
+func (game *Game) findWinningLine() []int {
+	rows, columns, winLength := game.Rows(), game.Columns(), game.winLength()
+
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			piece := game.Board[row][column]
+			if piece == EMPTY {
+				continue
+			}
+
+			for _, direction := range lineDirections {
+				length := 1
+				for length < winLength {
+					r, c := row+length*direction[0], column+length*direction[1]
+					if r < 0 || r >= rows || c < 0 || c >= columns || game.Board[r][c] != piece {
+						break
+					}
+					length++
+				}
+
+				if length >= winLength {
+					line := make([]int, winLength)
+					for i := range line {
+						line[i] = (row+i*direction[0])*columns + column + i*direction[1]
+					}
+					return line
+				}
+			}
+		}
 	}
 
-	return false, EMPTY
+	return nil
 }
 
 func (game *Game) isBoardFull() bool {
@@ -169,34 +501,21 @@ func (game *Game) isBoardFull() bool {
 }
 
 func (game *Game) isDraw() bool {
-	if !game.isBoardFull() {
+	rules := game.rules()
+	if !rules.IsTerminal(game) {
 		return false
 	}
 
-	isWinner, _ := game.isWinner()
+	winner, _ := rules.Outcome(game)
 
-	return !isWinner
+	return winner == EMPTY
 }
 
-func (game *Game) isValidMove(square int) bool {
-	if square < 0 || square > 8 {
-		return false
-	}
-
-	row, column := square/3, square%3
-
-	if game.Board[row][column] != EMPTY {
-		return false
-	}
-
-	return true
-}
-
-func areItemsInArrayEqual(arr []Piece) bool {
-	for _, item := range arr {
-		if item != arr[0] {
-			return false
+func (game *Game) isValidMove(move int) bool {
+	for _, legalMove := range game.rules().LegalMoves(game) {
+		if legalMove == move {
+			return true
 		}
 	}
-	return true
+	return false
 }
@@ -1,10 +1,22 @@
 package game
 
 import (
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
+var testTime = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func init() {
+	now = func() time.Time {
+		return testTime
+	}
+}
+
 func defaultGame() Game {
 	return Game{
 		Board: [][]Piece{},
@@ -231,6 +243,25 @@ func TestGame_IsPlayer_NotAPlayer(t *testing.T) {
 	assert.Equal(t, false, game.IsPlayer("someOtherPlayer"))
 }
 
+func TestGame_CanView_Player(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	assert.Equal(t, true, game.CanView("playerO"))
+}
+
+func TestGame_CanView_PrivateGame(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	assert.Equal(t, false, game.CanView("someOtherPlayer"))
+}
+
+func TestGame_CanView_PublicGame(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.IsPublic = true
+
+	assert.Equal(t, true, game.CanView("someOtherPlayer"))
+}
+
 func TestGame_GetOtherPlayer_PlayerX(t *testing.T) {
 	game := NewGame("playerX", "playerO")
 
@@ -332,6 +363,412 @@ func TestGame_MakeMove_ValidMove(t *testing.T) {
 		{X, EMPTY, EMPTY},
 	}
 	expectedGame.CurrentTurn = O
+	expectedGame.Status = XWon
+	expectedGame.Winner = X
+	expectedGame.WinningLine = []int{0, 3, 6}
+	expectedGame.Moves = []Move{
+		{Player: "playerX", Move: 6, Square: 6, Piece: X, Timestamp: testTime},
+	}
 	assert.Equal(t, nil, err)
 	assert.Equal(t, expectedGame, game)
 }
+
+func TestGame_NewGameWithSize_Success(t *testing.T) {
+	game, err := NewGameWithSize("playerX", "playerO", 4, 5, 4)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 4, game.Rows())
+	assert.Equal(t, 5, game.Columns())
+	assert.Equal(t, 4, game.WinLength)
+	assert.Equal(t, X, game.CurrentTurn)
+	for _, row := range game.Board {
+		assert.Equal(t, []Piece{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY}, row)
+	}
+}
+
+func TestGame_NewGameWithSize_TooSmall(t *testing.T) {
+	_, err := NewGameWithSize("playerX", "playerO", 2, 3, 3)
+
+	assert.Equal(t, &InvalidBoardSizeError{rows: 2, columns: 3, winLength: 3}, err)
+}
+
+func TestGame_NewGameWithSize_TooBig(t *testing.T) {
+	_, err := NewGameWithSize("playerX", "playerO", 20, 3, 3)
+
+	assert.Equal(t, &InvalidBoardSizeError{rows: 20, columns: 3, winLength: 3}, err)
+}
+
+func TestGame_NewGameWithSize_WinLengthLongerThanBoard(t *testing.T) {
+	_, err := NewGameWithSize("playerX", "playerO", 4, 4, 5)
+
+	assert.Equal(t, &InvalidBoardSizeError{rows: 4, columns: 4, winLength: 5}, err)
+}
+
+func TestGame_IsWinner_NoWinLengthDefaultsToThree(t *testing.T) {
+	var game = Game{
+		Board: [][]Piece{
+			{EMPTY, EMPTY, EMPTY, EMPTY},
+			{O, O, O, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY},
+		},
+	}
+
+	var isWinner, winner = game.isWinner()
+
+	assert.Equal(t, isWinner, true)
+	assert.Equal(t, winner, O)
+}
+
+func TestGame_IsWinner_LargeBoardNotEnoughInARow(t *testing.T) {
+	var game = Game{
+		Board: [][]Piece{
+			{X, X, X, X, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+		},
+		WinLength: 5,
+	}
+
+	var isWinner, winner = game.isWinner()
+
+	assert.Equal(t, isWinner, false)
+	assert.Equal(t, winner, EMPTY)
+}
+
+func TestGame_IsWinner_LargeBoardRow(t *testing.T) {
+	var game = Game{
+		Board: [][]Piece{
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, X, X, X, X},
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+		},
+		WinLength: 4,
+	}
+
+	var isWinner, winner = game.isWinner()
+
+	assert.Equal(t, isWinner, true)
+	assert.Equal(t, winner, X)
+}
+
+func TestGame_IsWinner_LargeBoardColumn(t *testing.T) {
+	var game = Game{
+		Board: [][]Piece{
+			{EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, EMPTY, EMPTY, O},
+			{EMPTY, EMPTY, EMPTY, O},
+			{EMPTY, EMPTY, EMPTY, O},
+			{EMPTY, EMPTY, EMPTY, O},
+		},
+		WinLength: 4,
+	}
+
+	var isWinner, winner = game.isWinner()
+
+	assert.Equal(t, isWinner, true)
+	assert.Equal(t, winner, O)
+}
+
+func TestGame_IsWinner_LargeBoardDiag1(t *testing.T) {
+	var game = Game{
+		Board: [][]Piece{
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+			{EMPTY, X, EMPTY, EMPTY, EMPTY},
+			{EMPTY, EMPTY, X, EMPTY, EMPTY},
+			{EMPTY, EMPTY, EMPTY, X, EMPTY},
+			{EMPTY, EMPTY, EMPTY, EMPTY, EMPTY},
+		},
+		WinLength: 3,
+	}
+
+	var isWinner, winner = game.isWinner()
+
+	assert.Equal(t, isWinner, true)
+	assert.Equal(t, winner, X)
+}
+
+func TestGame_IsWinner_LargeBoardDiag2(t *testing.T) {
+	var game = Game{
+		Board: [][]Piece{
+			{EMPTY, EMPTY, EMPTY, EMPTY, O},
+			{EMPTY, EMPTY, EMPTY, O, EMPTY},
+			{EMPTY, EMPTY, O, EMPTY, EMPTY},
+			{EMPTY, O, EMPTY, EMPTY, EMPTY},
+		},
+		WinLength: 4,
+	}
+
+	var isWinner, winner = game.isWinner()
+
+	assert.Equal(t, isWinner, true)
+	assert.Equal(t, winner, O)
+}
+
+func TestGame_IsValidMove_LargeBoard(t *testing.T) {
+	game, _ := NewGameWithSize("playerX", "playerO", 4, 5, 4)
+
+	assert.Equal(t, true, game.isValidMove(19))
+	assert.Equal(t, false, game.isValidMove(20))
+}
+
+func TestGame_MakeMove_LargeBoard(t *testing.T) {
+	game, _ := NewGameWithSize("playerX", "playerO", 4, 5, 4)
+
+	err := game.MakeMove("playerX", 7)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, X, game.Board[1][2])
+	assert.Equal(t, O, game.CurrentTurn)
+}
+
+func TestGame_CurrentPlayer(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	assert.Equal(t, "playerX", game.CurrentPlayer())
+
+	game.CurrentTurn = O
+	assert.Equal(t, "playerO", game.CurrentPlayer())
+}
+
+func TestGame_IsFinished_InProgress(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, O, X},
+		{EMPTY, O, EMPTY},
+		{EMPTY, EMPTY, EMPTY},
+	}
+
+	assert.Equal(t, false, game.IsFinished())
+}
+
+func TestGame_IsFinished_Winner(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, O, X},
+		{EMPTY, O, X},
+		{EMPTY, O, EMPTY},
+	}
+
+	assert.Equal(t, true, game.IsFinished())
+}
+
+func TestGame_IsFinished_Draw(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, O, X},
+		{X, O, O},
+		{O, X, X},
+	}
+
+	assert.Equal(t, true, game.IsFinished())
+}
+
+func TestGame_LegalMoves_InProgress(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, O, X},
+		{EMPTY, O, EMPTY},
+		{EMPTY, X, EMPTY},
+	}
+
+	assert.Equal(t, []int{3, 5, 6, 8}, game.LegalMoves())
+}
+
+func TestGame_LegalMoves_Finished(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, X, X},
+		{EMPTY, O, EMPTY},
+		{EMPTY, O, EMPTY},
+	}
+
+	assert.Equal(t, []int{}, game.LegalMoves())
+}
+
+func TestGame_Clone(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	clone := game.Clone()
+	clone.Board[1][1] = X
+
+	assert.Equal(t, EMPTY, game.Board[1][1])
+	assert.Equal(t, X, clone.Board[1][1])
+}
+
+func TestGame_MakeMove_RecordsMoves(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	_ = game.MakeMove("playerX", 4)
+	_ = game.MakeMove("playerO", 0)
+	_ = game.MakeMove("playerO", 1)
+
+	assert.Equal(t, []Move{
+		{Player: "playerX", Move: 4, Square: 4, Piece: X, Timestamp: testTime},
+		{Player: "playerO", Move: 0, Square: 0, Piece: O, Timestamp: testTime},
+	}, game.Moves)
+}
+
+func TestGame_ReplayTo_Start(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.MakeMove("playerX", 4)
+	_ = game.MakeMove("playerO", 0)
+
+	replay, err := game.ReplayTo(0)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, NewGame("playerX", "playerO"), replay)
+}
+
+func TestGame_ReplayTo_MidGame(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.MakeMove("playerX", 4)
+	_ = game.MakeMove("playerO", 0)
+	_ = game.MakeMove("playerX", 8)
+
+	replay, err := game.ReplayTo(2)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]Piece{
+		{O, EMPTY, EMPTY},
+		{EMPTY, X, EMPTY},
+		{EMPTY, EMPTY, EMPTY},
+	}, replay.Board)
+	assert.Equal(t, X, replay.CurrentTurn)
+	assert.Equal(t, game.Moves[:2], replay.Moves)
+}
+
+func TestGame_ReplayTo_End(t *testing.T) {
+	game, _ := NewGameWithSize("playerX", "playerO", 4, 4, 3)
+	_ = game.MakeMove("playerX", 5)
+	_ = game.MakeMove("playerO", 15)
+
+	replay, err := game.ReplayTo(2)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, game, replay)
+}
+
+func TestGame_ReplayTo_InvalidPly(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	_ = game.MakeMove("playerX", 4)
+
+	_, err := game.ReplayTo(2)
+	assert.Equal(t, &InvalidPlyError{ply: 2, moves: 1}, err)
+
+	_, err = game.ReplayTo(-1)
+	assert.Equal(t, &InvalidPlyError{ply: -1, moves: 1}, err)
+}
+
+func TestGame_NewGame_InProgress(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	assert.Equal(t, InProgress, game.Status)
+	assert.Equal(t, false, game.IsFinished())
+}
+
+func TestGame_MakeMove_StillInProgress(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+
+	_ = game.MakeMove("playerX", 4)
+
+	assert.Equal(t, InProgress, game.Status)
+	assert.Equal(t, Piece(""), game.Winner)
+	assert.Nil(t, game.WinningLine)
+}
+
+func TestGame_MakeMove_SetsWinner(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, X, O},
+		{EMPTY, O, EMPTY},
+		{EMPTY, EMPTY, X},
+	}
+	game.CurrentTurn = O
+
+	err := game.MakeMove("playerO", 6)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OWon, game.Status)
+	assert.Equal(t, O, game.Winner)
+	assert.Equal(t, []int{2, 4, 6}, game.WinningLine)
+	assert.Equal(t, true, game.IsFinished())
+}
+
+func TestGame_MakeMove_SetsDraw(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Board = [][]Piece{
+		{X, O, X},
+		{X, O, O},
+		{O, X, EMPTY},
+	}
+
+	err := game.MakeMove("playerX", 8)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Draw, game.Status)
+	assert.Equal(t, Piece(""), game.Winner)
+	assert.Nil(t, game.WinningLine)
+}
+
+func TestGame_MakeMove_AfterStatusFinished(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Status = Resigned
+	game.Winner = O
+
+	err := game.MakeMove("playerX", 0)
+
+	expectedWinner := O
+	assert.Equal(t, &FinishedError{winner: &expectedWinner}, err)
+}
+
+func TestGame_FindWinningLine_LargeBoard(t *testing.T) {
+	var game = Game{
+		Board: [][]Piece{
+			{EMPTY, EMPTY, EMPTY, EMPTY, O},
+			{EMPTY, EMPTY, EMPTY, O, EMPTY},
+			{EMPTY, EMPTY, O, EMPTY, EMPTY},
+			{EMPTY, O, EMPTY, EMPTY, EMPTY},
+		},
+		WinLength: 4,
+	}
+
+	assert.Equal(t, []int{4, 8, 12, 16}, game.findWinningLine())
+}
+
+func TestErrors_Is(t *testing.T) {
+	winner := X
+	tests := []struct {
+		err   error
+		class error
+	}{
+		{err: &InvalidBoardSizeError{}, class: errs.ErrInvalid},
+		{err: &InvalidMoveError{}, class: errs.ErrInvalid},
+		{err: &NotPlayersTurnError{}, class: errs.ErrInvalid},
+		{err: &InvalidPlyError{}, class: errs.ErrInvalid},
+		{err: &InvalidTimeControlError{}, class: errs.ErrInvalid},
+		{err: &InvalidNotationError{}, class: errs.ErrInvalid},
+		{err: &UnknownVariantError{}, class: errs.ErrInvalid},
+		{err: &UnsupportedBitboardError{}, class: errs.ErrInvalid},
+		{err: &NotSquareError{}, class: errs.ErrInvalid},
+		{err: &HintsDisabledError{}, class: errs.ErrInvalid},
+		{err: &PositionTooLargeError{}, class: errs.ErrInvalid},
+		{err: &PlayerDoesNotExistError{}, class: errs.ErrForbidden},
+		{err: &FinishedError{winner: &winner}, class: errs.ErrConflict},
+		{err: &OutOfTimeError{}, class: errs.ErrConflict},
+		{err: &DrawAlreadyOfferedError{}, class: errs.ErrConflict},
+		{err: &NoDrawOfferError{}, class: errs.ErrConflict},
+		{err: &NotFinishedError{}, class: errs.ErrConflict},
+		{err: &RematchExistsError{}, class: errs.ErrConflict},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%T", test.err), func(t *testing.T) {
+			assert.True(t, errors.Is(test.err, test.class))
+			assert.True(t, errors.Is(fmt.Errorf("wrapped - %w", test.err), test.class))
+			assert.False(t, errors.Is(test.err, errs.ErrRetryable))
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The results a game's notation can end with
+const (
+	XWinResult        = "1-0"
+	OWinResult        = "0-1"
+	DrawResult        = "1/2-1/2"
+	UnfinishedResult  = "*"
+	notationDate      = "2006.01.02"
+	unknownDate       = "????.??.??"
+	notationLineWidth = 80
+)
+
+type InvalidNotationError struct {
+	line   int
+	reason string
+}
+
+func (e *InvalidNotationError) Error() string {
+	return fmt.Sprintf("invalid notation on line %d - %s", e.line, e.reason)
+}
+
+func (e *InvalidNotationError) Code() string {
+	return "INVALID_NOTATION"
+}
+
+func (e *InvalidNotationError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+// ImportedOpponentId is stored in place of the other player of an imported game. Only the player importing it is known
+// to have played the game, so naming anyone else would add it to their games
+const ImportedOpponentId = "imported-opponent"
+
+var headerPattern = regexp.MustCompile(`^\[([A-Za-z0-9_]+)\s+"((?:[^"\\]|\\.)*)"\]$`)
+
+// Notation writes the game as text in the style of chess's Portable Game Notation: a header of tag pairs naming the
+// players, rules, board size, date and result, followed by the numbered list of moves, each with the time it was played
+// as a comment, and the result again. Moves are written as the same numbers MakeMove takes for the game's variant
+func (game *Game) Notation() string {
+	var builder strings.Builder
+
+	writeHeader := func(name string, value string) {
+		value = strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+		fmt.Fprintf(&builder, "[%s \"%s\"]\n", name, value)
+	}
+
+	if game.Id != "" {
+		writeHeader("Id", game.Id)
+	}
+	writeHeader("PlayerX", game.PlayerX)
+	writeHeader("PlayerO", game.PlayerO)
+	if game.ComputerDifficulty != "" {
+		writeHeader("Computer", game.ComputerDifficulty)
+	}
+	variant := game.Variant
+	if variant == "" {
+		variant = Classic
+	}
+	writeHeader("Variant", string(variant))
+	writeHeader("Rows", strconv.Itoa(game.Rows()))
+	writeHeader("Columns", strconv.Itoa(game.Columns()))
+	writeHeader("WinLength", strconv.Itoa(game.winLength()))
+	date := unknownDate
+	if len(game.Moves) > 0 {
+		date = game.Moves[0].Timestamp.UTC().Format(notationDate)
+	}
+	writeHeader("Date", date)
+	writeHeader("Result", game.result())
+	switch game.Status {
+	case Resigned, TimedOut, DrawAgreed, Abandoned:
+		writeHeader("Termination", string(game.Status))
+	}
+	builder.WriteString("\n")
+
+	line := ""
+	writeToken := func(token string) {
+		if line != "" && len(line)+1+len(token) > notationLineWidth {
+			builder.WriteString(line + "\n")
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += token
+	}
+
+	for i, move := range game.Moves {
+		if i%2 == 0 {
+			writeToken(fmt.Sprintf("%d.", i/2+1))
+		}
+		writeToken(strconv.Itoa(move.Move))
+		writeToken("{" + move.Timestamp.UTC().Format(time.RFC3339Nano) + "}")
+	}
+	writeToken(game.result())
+	builder.WriteString(line + "\n")
+
+	return builder.String()
+}
+
+// notationHeaders are the tag pairs at the top of a game's notation, with the line each was read from
+type notationHeaders struct {
+	values map[string]string
+	lines  map[string]int
+	// last is the line of the last header, which errors about missing headers are reported on
+	last int
+}
+
+// lineOf is the line of the first of the headers that was given, or of the last header if none of them were
+func (h notationHeaders) lineOf(names ...string) int {
+	for _, name := range names {
+		if line, ok := h.lines[name]; ok {
+			return line
+		}
+	}
+	if h.last == 0 {
+		return 1
+	}
+	return h.last
+}
+
+// ParseNotation reads a game written by Notation, replaying its moves through the rules of its variant so that the
+// game returned is exactly as it was when it was written, apart from its clock. Only the PlayerX and PlayerO headers
+// are required, and comments that are not timestamps are ignored. The Date header is checked but not kept, as the
+// timestamp of each move already says when it was played
+func ParseNotation(text string) (*Game, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	headers := notationHeaders{values: map[string]string{}, lines: map[string]int{}}
+	lineNumber := 0
+	for ; lineNumber < len(lines); lineNumber++ {
+		line := strings.TrimSpace(lines[lineNumber])
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "[") {
+			break
+		}
+
+		match := headerPattern.FindStringSubmatch(line)
+		if match == nil {
+			return nil, &InvalidNotationError{line: lineNumber + 1, reason: fmt.Sprintf("%s is not a header", line)}
+		}
+		headers.values[match[1]] = strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(match[2])
+		headers.lines[match[1]] = lineNumber + 1
+		headers.last = lineNumber + 1
+	}
+
+	g, err := newGameFromHeaders(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	result, resultLine, err := replayMovetext(g, lines[lineNumber:], lineNumber)
+	if err != nil {
+		return nil, err
+	}
+	if header, ok := headers.values["Result"]; ok && header != result {
+		return nil, &InvalidNotationError{
+			line:   headers.lineOf("Result"),
+			reason: fmt.Sprintf("the moves end with %s but the Result header is %s", result, header),
+		}
+	}
+
+	if err := finishFromNotation(g, headers, result, resultLine); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func newGameFromHeaders(headers notationHeaders) (*Game, error) {
+	for _, name := range []string{"PlayerX", "PlayerO"} {
+		if _, ok := headers.values[name]; !ok {
+			return nil, &InvalidNotationError{line: headers.lineOf(), reason: fmt.Sprintf("the %s header is missing", name)}
+		}
+	}
+
+	size := map[string]int{}
+	for _, name := range []string{"Rows", "Columns", "WinLength"} {
+		value, ok := headers.values[name]
+		if !ok {
+			continue
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, &InvalidNotationError{line: headers.lineOf(name), reason: fmt.Sprintf("the %s header %s is not a number", name, value)}
+		}
+		size[name] = number
+	}
+
+	if date, ok := headers.values["Date"]; ok && date != unknownDate {
+		if _, err := time.Parse(notationDate, date); err != nil {
+			return nil, &InvalidNotationError{line: headers.lineOf("Date"), reason: fmt.Sprintf("the Date header %s is not a date", date)}
+		}
+	}
+
+	values := headers.values
+	g, err := NewVariantGame(Variant(values["Variant"]), values["PlayerX"], values["PlayerO"], size["Rows"], size["Columns"], size["WinLength"])
+	if err != nil {
+		return nil, &InvalidNotationError{line: headers.lineOf("Variant", "Rows", "Columns", "WinLength"), reason: err.Error()}
+	}
+	g.Id = values["Id"]
+	g.ComputerDifficulty = values["Computer"]
+
+	return g, nil
+}
+
+// replayMovetext plays the moves listed after the headers, which start at line offset, and returns the result the
+// list ends with and the line it is on
+func replayMovetext(g *Game, lines []string, offset int) (string, int, error) {
+	result, resultLine := "", 0
+	for i, line := range lines {
+		lineNumber := offset + i + 1
+		invalid := func(format string, a ...interface{}) error {
+			return &InvalidNotationError{line: lineNumber, reason: fmt.Sprintf(format, a...)}
+		}
+
+		for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+			if result != "" {
+				return "", 0, invalid("%s follows the result", line)
+			}
+
+			if strings.HasPrefix(line, "{") {
+				end := strings.Index(line, "}")
+				if end < 0 {
+					return "", 0, invalid("a comment is not closed")
+				}
+				comment := strings.TrimSpace(line[1:end])
+				line = line[end+1:]
+
+				timestamp, err := time.Parse(time.RFC3339Nano, comment)
+				if err == nil && len(g.Moves) > 0 {
+					g.Moves[len(g.Moves)-1].Timestamp = timestamp.UTC()
+				}
+				continue
+			}
+
+			token := line
+			if end := strings.IndexAny(line, " \t{"); end >= 0 {
+				token = line[:end]
+			}
+			line = line[len(token):]
+
+			switch {
+			case token == XWinResult || token == OWinResult || token == DrawResult || token == UnfinishedResult:
+				result, resultLine = token, lineNumber
+			case strings.HasSuffix(token, "."):
+				number, err := strconv.Atoi(strings.TrimRight(token, "."))
+				if err != nil {
+					return "", 0, invalid("%s is not a move number", token)
+				}
+				if number != len(g.Moves)/2+1 {
+					return "", 0, invalid("move %d is numbered %d", len(g.Moves)/2+1, number)
+				}
+			default:
+				move, err := strconv.Atoi(token)
+				if err != nil {
+					return "", 0, invalid("%s is not a move", token)
+				}
+				if err := g.MakeMove(g.CurrentPlayer(), move); err != nil {
+					return "", 0, invalid("ply %d - %s", len(g.Moves)+1, err)
+				}
+			}
+		}
+	}
+
+	if result == "" {
+		return "", 0, &InvalidNotationError{line: offset + len(lines), reason: "the moves do not end with a result"}
+	}
+	return result, resultLine, nil
+}
+
+// finishFromNotation checks the result against the position the moves reach, ending the game the way the Termination
+// header says if the moves alone did not end it. The result itself is on resultLine
+func finishFromNotation(g *Game, headers notationHeaders, result string, resultLine int) error {
+	termination := headers.values["Termination"]
+	if termination == "" {
+		if g.result() != result {
+			return &InvalidNotationError{
+				line:   resultLine,
+				reason: fmt.Sprintf("the moves end with the game %s, which is not a result of %s", g.Status, result),
+			}
+		}
+		return nil
+	}
+
+	if g.Status != InProgress {
+		return &InvalidNotationError{line: headers.lineOf("Termination"), reason: fmt.Sprintf("the game was %s before it was %s", g.Status, termination)}
+	}
+
+	results := map[Status]map[string]Piece{
+		Resigned:   {XWinResult: X, OWinResult: O},
+		TimedOut:   {XWinResult: X, OWinResult: O},
+		DrawAgreed: {DrawResult: ""},
+		Abandoned:  {UnfinishedResult: ""},
+	}
+	winners, ok := results[Status(termination)]
+	if !ok {
+		return &InvalidNotationError{line: headers.lineOf("Termination"), reason: fmt.Sprintf("%s is not a way for a game to end", termination)}
+	}
+	winner, ok := winners[result]
+	if !ok {
+		return &InvalidNotationError{line: headers.lineOf("Termination"), reason: fmt.Sprintf("a game that was %s cannot have a result of %s", termination, result)}
+	}
+
+	g.finish(Status(termination), winner)
+	return nil
+}
+
+func (game *Game) result() string {
+	switch {
+	case game.Winner == X:
+		return XWinResult
+	case game.Winner == O:
+		return OWinResult
+	case game.Status == Draw || game.Status == DrawAgreed:
+		return DrawResult
+	default:
+		return UnfinishedResult
+	}
+}
@@ -0,0 +1,281 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGame_Notation(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Id = "game-id"
+	playMoves(t, game, 4, 0, 2, 6)
+	setNow(t, testTime.Add(90*time.Second))
+	playMoves(t, game, 3, 5)
+	assert.Equal(t, nil, game.Resign("playerX"))
+
+	expected := `[Id "game-id"]
+[PlayerX "playerX"]
+[PlayerO "playerO"]
+[Variant "classic"]
+[Rows "3"]
+[Columns "3"]
+[WinLength "3"]
+[Date "2023.06.01"]
+[Result "0-1"]
+[Termination "RESIGNED"]
+
+1. 4 {2023-06-01T12:00:00Z} 0 {2023-06-01T12:00:00Z} 2. 2 {2023-06-01T12:00:00Z}
+6 {2023-06-01T12:00:00Z} 3. 3 {2023-06-01T12:01:30Z} 5 {2023-06-01T12:01:30Z}
+0-1
+`
+	assert.Equal(t, expected, game.Notation())
+}
+
+func TestGame_Notation_NoMoves(t *testing.T) {
+	game := NewGame("player \"X\"", "playerO")
+
+	expected := `[PlayerX "player \"X\""]
+[PlayerO "playerO"]
+[Variant "classic"]
+[Rows "3"]
+[Columns "3"]
+[WinLength "3"]
+[Date "????.??.??"]
+[Result "*"]
+
+*
+`
+	assert.Equal(t, expected, game.Notation())
+}
+
+func TestParseNotation_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		rows    int
+		moves   []int
+		finish  func(game *Game)
+	}{
+		{
+			name:  "won",
+			moves: []int{0, 3, 1, 4, 2},
+		},
+		{
+			name:  "drawn",
+			moves: []int{4, 0, 2, 6, 3, 5, 1, 7, 8},
+		},
+		{
+			name:  "in progress",
+			moves: []int{4, 0},
+		},
+		{
+			name:  "on a larger board",
+			rows:  15,
+			moves: []int{112, 113, 97},
+		},
+		{
+			name:  "resigned",
+			moves: []int{4},
+			finish: func(game *Game) {
+				_ = game.Resign("playerO")
+			},
+		},
+		{
+			name:  "draw agreed",
+			moves: []int{4, 0},
+			finish: func(game *Game) {
+				_ = game.OfferDraw("playerX")
+				_ = game.AcceptDraw("playerO")
+			},
+		},
+		{
+			name:  "abandoned",
+			moves: []int{4},
+			finish: func(game *Game) {
+				game.Abandon()
+			},
+		},
+		{
+			name:    "misere",
+			variant: Misere,
+			moves:   []int{0, 3, 1, 4, 2},
+		},
+		{
+			name:    "wild",
+			variant: Wild,
+			moves:   []int{WildMove(0, O), WildMove(4, X), WildMove(8, O)},
+		},
+		{
+			name:    "numerical",
+			variant: Numerical,
+			moves:   []int{NumericalMove(0, 9), NumericalMove(4, 2), NumericalMove(8, 3)},
+		},
+		{
+			name:    "ultimate",
+			variant: Ultimate,
+			moves:   []int{UltimateMove(4, 0), UltimateMove(0, 4), UltimateMove(4, 8)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := NewVariantGame(test.variant, "playerX", "playerO", test.rows, test.rows, 0)
+			assert.Equal(t, nil, err)
+			game.Id = "game-id"
+			playMoves(t, game, test.moves...)
+			if test.finish != nil {
+				test.finish(game)
+			}
+
+			parsed, err := ParseNotation(game.Notation())
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, game, parsed)
+		})
+	}
+}
+
+func TestParseNotation_Minimal(t *testing.T) {
+	notation := `[PlayerX "playerX"]
+[PlayerO "playerO"]
+
+1. 4 {a good start} 0 2... 8 *`
+
+	game, err := ParseNotation(notation)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Classic, game.Variant)
+	assert.Equal(t, 3, len(game.Moves))
+	assert.Equal(t, testTime, game.Moves[0].Timestamp)
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestParseNotation_DateIsNotKept(t *testing.T) {
+	notation := `[PlayerX "playerX"]
+[PlayerO "playerO"]
+[Date "1999.12.31"]
+
+1. 4 {2023-06-02T09:30:00Z} *`
+
+	game, err := ParseNotation(notation)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2023, 6, 2, 9, 30, 0, 0, time.UTC), game.Moves[0].Timestamp)
+	assert.Contains(t, game.Notation(), `[Date "2023.06.02"]`)
+}
+
+func TestParseNotation_Invalid(t *testing.T) {
+	tests := []struct {
+		name         string
+		notation     string
+		expectedLine int
+	}{
+		{
+			name:         "malformed header",
+			notation:     "[PlayerX playerX]\n[PlayerO \"playerO\"]\n\n*",
+			expectedLine: 1,
+		},
+		{
+			name:         "missing player",
+			notation:     "[PlayerX \"playerX\"]\n\n*",
+			expectedLine: 1,
+		},
+		{
+			name:         "unknown variant",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Variant \"cubic\"]\n\n*",
+			expectedLine: 3,
+		},
+		{
+			name:         "board size is not a number",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Rows \"three\"]\n\n*",
+			expectedLine: 3,
+		},
+		{
+			name:         "invalid board size",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Rows \"2\"]\n\n*",
+			expectedLine: 3,
+		},
+		{
+			name:         "malformed header after the first",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO playerO]\n\n*",
+			expectedLine: 2,
+		},
+		{
+			name:         "malformed date",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Date \"June 1st\"]\n\n*",
+			expectedLine: 3,
+		},
+		{
+			name:         "illegal move",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 4 4 *",
+			expectedLine: 4,
+		},
+		{
+			name:         "move is not a number",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. b2 *",
+			expectedLine: 4,
+		},
+		{
+			name:         "wrong move number",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 4 0 3. 8 *",
+			expectedLine: 4,
+		},
+		{
+			name:         "unclosed comment",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 4 {comment *",
+			expectedLine: 4,
+		},
+		{
+			name:         "no result",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 4 0",
+			expectedLine: 4,
+		},
+		{
+			name:         "moves after the result",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 4 * 0",
+			expectedLine: 4,
+		},
+		{
+			name:         "result header does not match",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Result \"1-0\"]\n\n1. 4 *",
+			expectedLine: 3,
+		},
+		{
+			name:         "result does not match the board",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 0 3 2. 1 4 3. 2 0-1",
+			expectedLine: 4,
+		},
+		{
+			name:         "unfinished game claims a win",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 4 1-0",
+			expectedLine: 4,
+		},
+		{
+			name:         "resigned with a draw",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Termination \"RESIGNED\"]\n\n1. 4 1/2-1/2",
+			expectedLine: 3,
+		},
+		{
+			name:         "resigned after the game was won",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Termination \"RESIGNED\"]\n\n1. 0 3 2. 1 4 3. 2 1-0",
+			expectedLine: 3,
+		},
+		{
+			name:         "unknown termination",
+			notation:     "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n[Termination \"FORFEITED\"]\n\n1. 4 1-0",
+			expectedLine: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := ParseNotation(test.notation)
+
+			assert.Nil(t, game)
+			assert.IsType(t, &InvalidNotationError{}, err)
+			assert.Equal(t, "INVALID_NOTATION", err.(*InvalidNotationError).Code())
+			assert.Equal(t, test.expectedLine, err.(*InvalidNotationError).line)
+		})
+	}
+}
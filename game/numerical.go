@@ -0,0 +1,109 @@
+package game
+
+import "strconv"
+
+// Numerical tic-tac-toe is played on a 3x3 board with the numbers 1-9 in place of pieces, each of which can only be
+// played once. X plays the odd numbers and O the even ones, and whoever completes a line adding up to 15 wins
+const (
+	numericalBoardSize = 3
+	maxNumber          = 9
+	numericalTarget    = 15
+)
+
+type numericalRules struct{}
+
+// NumericalMove encodes a move in numerical tic-tac-toe as the square played and the number placed there
+func NumericalMove(square int, number int) int {
+	return square*10 + number
+}
+
+func (numericalRules) NewGame(playerX string, playerO string, rows int, columns int, winLength int) (*Game, error) {
+	err := checkFixedSize(rows, columns, winLength, numericalBoardSize, numericalBoardSize, numericalBoardSize)
+	if err != nil {
+		return nil, err
+	}
+	return NewGameWithSize(playerX, playerO, numericalBoardSize, numericalBoardSize, numericalBoardSize)
+}
+
+func (numericalRules) LegalMoves(game *Game) []int {
+	var used [maxNumber + 1]bool
+	for _, row := range game.Board {
+		for _, cell := range row {
+			if number, err := strconv.Atoi(string(cell)); err == nil {
+				used[number] = true
+			}
+		}
+	}
+
+	var moves []int
+	for _, square := range game.emptySquares() {
+		for number := firstNumber(game.CurrentTurn); number <= maxNumber; number += 2 {
+			if !used[number] {
+				moves = append(moves, NumericalMove(square, number))
+			}
+		}
+	}
+	return moves
+}
+
+func (numericalRules) ApplyMove(game *Game, move int) (int, Piece) {
+	piece := numberPiece(move % 10)
+	game.place(move/10, piece)
+	return move / 10, piece
+}
+
+func (numericalRules) IsTerminal(game *Game) bool {
+	return game.findNumericalLine() != nil || game.isBoardFull()
+}
+
+func (numericalRules) Outcome(game *Game) (Piece, []int) {
+	line := game.findNumericalLine()
+	if line == nil {
+		return EMPTY, nil
+	}
+	return opponentOf(game.CurrentTurn), line
+}
+
+// firstNumber is the lowest number the player with the given piece can play: X plays the odd numbers and O the even
+func firstNumber(piece Piece) int {
+	if piece == X {
+		return 1
+	}
+	return 2
+}
+
+func numberPiece(number int) Piece {
+	return Piece(strconv.Itoa(number))
+}
+
+// findNumericalLine returns the squares of the first full line adding up to 15, or nil if there is none
+func (game *Game) findNumericalLine() []int {
+	rows, columns := game.Rows(), game.Columns()
+
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			for _, direction := range lineDirections {
+				var line []int
+				sum := 0
+				for len(line) < numericalBoardSize {
+					r, c := row+len(line)*direction[0], column+len(line)*direction[1]
+					if r < 0 || r >= rows || c < 0 || c >= columns {
+						break
+					}
+					number, err := strconv.Atoi(string(game.Board[r][c]))
+					if err != nil {
+						break
+					}
+					line = append(line, r*columns+c)
+					sum += number
+				}
+
+				if len(line) == numericalBoardSize && sum == numericalTarget {
+					return line
+				}
+			}
+		}
+	}
+
+	return nil
+}
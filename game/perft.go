@@ -0,0 +1,98 @@
+package game
+
+// PerftToEnd is the depth to give Perft to play every game out to the end
+const PerftToEnd = -1
+
+// PerftResult counts every line of play from a starting position
+type PerftResult struct {
+	// Nodes is the number of positions reached after each number of plies, starting with the starting position itself
+	Nodes []int
+	// XWins, OWins and Draws count the games that end in each result within the depth searched
+	XWins int
+	OWins int
+	Draws int
+	// TerminalPositions counts the distinct positions those games end in, and SymmetricTerminalPositions counts them
+	// again with positions that are rotations or reflections of each other only counted once
+	TerminalPositions          int
+	SymmetricTerminalPositions int
+}
+
+// Games is the number of games that ended within the depth searched
+func (r PerftResult) Games() int {
+	return r.XWins + r.OWins + r.Draws
+}
+
+// Perft plays every sequence of legal moves from the game's current position, up to depth plies, through MakeMove. It
+// is slow, but checks the rules of every variant exactly as they are played
+func Perft(g *Game, depth int) PerftResult {
+	start := g.Clone()
+	start.Moves = nil
+	start.TimeControl = TimeControl{}
+	start.Deadline = 0
+
+	p := &perft{
+		depth:      depth,
+		symmetries: boardSymmetries(g.Rows(), g.Columns()),
+		terminal:   map[string]bool{},
+		symmetric:  map[string]bool{},
+	}
+	p.search(start, 0)
+
+	p.result.TerminalPositions = len(p.terminal)
+	p.result.SymmetricTerminalPositions = len(p.symmetric)
+	return p.result
+}
+
+type perft struct {
+	depth      int
+	symmetries [][]int
+	terminal   map[string]bool
+	symmetric  map[string]bool
+	result     PerftResult
+}
+
+func (p *perft) search(g *Game, ply int) {
+	if len(p.result.Nodes) <= ply {
+		p.result.Nodes = append(p.result.Nodes, 0)
+	}
+	p.result.Nodes[ply]++
+
+	if g.IsFinished() {
+		p.countTerminal(g)
+		return
+	}
+	if ply == p.depth {
+		return
+	}
+
+	for _, move := range g.LegalMoves() {
+		next := g.Clone()
+		_ = next.MakeMove(next.CurrentPlayer(), move)
+		p.search(next, ply+1)
+	}
+}
+
+func (p *perft) countTerminal(g *Game) {
+	switch g.Winner {
+	case X:
+		p.result.XWins++
+	case O:
+		p.result.OWins++
+	default:
+		p.result.Draws++
+	}
+
+	key := boardKey(g, p.symmetries[0])
+	if p.terminal[key] {
+		return
+	}
+	p.terminal[key] = true
+
+	smallest := key
+	for _, symmetry := range p.symmetries[1:] {
+		if k := boardKey(g, symmetry); k < smallest {
+			smallest = k
+		}
+	}
+	p.symmetric[smallest] = true
+}
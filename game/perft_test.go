@@ -0,0 +1,77 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPerft_Classic(t *testing.T) {
+	result := Perft(NewGame("playerX", "playerO"), PerftToEnd)
+
+	assert.Equal(t, []int{1, 9, 72, 504, 3024, 15120, 54720, 148176, 200448, 127872}, result.Nodes)
+	assert.Equal(t, 131184, result.XWins)
+	assert.Equal(t, 77904, result.OWins)
+	assert.Equal(t, 46080, result.Draws)
+	assert.Equal(t, 255168, result.Games())
+	assert.Equal(t, 958, result.TerminalPositions)
+	assert.Equal(t, 138, result.SymmetricTerminalPositions)
+}
+
+func TestPerft_Misere(t *testing.T) {
+	game, _ := NewVariantGame(Misere, "playerX", "playerO", 0, 0, 0)
+
+	result := Perft(game, PerftToEnd)
+
+	// The game tree is the same as the classic one, with whoever completes a line losing instead of winning
+	assert.Equal(t, 77904, result.XWins)
+	assert.Equal(t, 131184, result.OWins)
+	assert.Equal(t, 46080, result.Draws)
+	assert.Equal(t, 138, result.SymmetricTerminalPositions)
+}
+
+func TestPerft_Notakto(t *testing.T) {
+	game, _ := NewVariantGame(Notakto, "playerX", "playerO", 0, 0, 0)
+
+	result := Perft(game, PerftToEnd)
+
+	// Nine Xs always make a line, so a game of Notakto can't be drawn, and whoever completes it loses
+	assert.Equal(t, 12864, result.XWins)
+	assert.Equal(t, 10368, result.OWins)
+	assert.Equal(t, 0, result.Draws)
+	assert.Equal(t, 23232, result.Games())
+}
+
+func TestPerft_Depth(t *testing.T) {
+	result := Perft(NewGame("playerX", "playerO"), 3)
+
+	assert.Equal(t, []int{1, 9, 72, 504}, result.Nodes)
+	assert.Equal(t, 0, result.Games())
+}
+
+func TestPerft_FromPosition(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	playMoves(t, game, 4, 0, 2)
+
+	result := Perft(game, PerftToEnd)
+
+	assert.Equal(t, []int{1, 6, 30}, result.Nodes[:3])
+	// Every game that can follow X in the centre, O in a corner and X in the next corner along
+	assert.Equal(t, 273, result.XWins)
+	assert.Equal(t, 96, result.OWins)
+	assert.Equal(t, 72, result.Draws)
+	assert.Equal(t, 3, len(game.Moves), "the game itself is left alone")
+}
+
+func TestPerft_Finished(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	playMoves(t, game, 0, 3, 1, 4, 2)
+
+	result := Perft(game, PerftToEnd)
+
+	assert.Equal(t, PerftResult{
+		Nodes:                      []int{1},
+		XWins:                      1,
+		TerminalPositions:          1,
+		SymmetricTerminalPositions: 1,
+	}, result)
+}
@@ -0,0 +1,239 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+)
+
+type Variant string
+
+const (
+	Classic   Variant = "classic"
+	Misere    Variant = "misere"
+	Notakto   Variant = "notakto"
+	Wild      Variant = "wild"
+	Numerical Variant = "numerical"
+	Ultimate  Variant = "ultimate"
+)
+
+type UnknownVariantError struct {
+	variant Variant
+}
+
+func (e *UnknownVariantError) Error() string {
+	return fmt.Sprintf("%s is not a valid variant", e.variant)
+}
+
+func (e *UnknownVariantError) Code() string {
+	return "UNKNOWN_VARIANT"
+}
+
+func (e *UnknownVariantError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+// Rules are what set one variant of tic-tac-toe apart from another. Everything else about a game, such as taking
+// turns, clocks and draw offers, is shared by every variant and handled by Game
+type Rules interface {
+	// NewGame sets up a game of the variant, with zero for any size that should be left at the variant's default
+	NewGame(playerX string, playerO string, rows int, columns int, winLength int) (*Game, error)
+	// LegalMoves lists the moves the current player could make, ignoring whether the game has already finished
+	LegalMoves(game *Game) []int
+	// ApplyMove plays a legal move for the current player and returns the square of Board it was played on and the
+	// piece that was placed there
+	ApplyMove(game *Game, move int) (int, Piece)
+	// IsTerminal reports whether the board has decided the game
+	IsTerminal(game *Game) bool
+	// Outcome returns the winner of a decided game and the squares that decided it, with EMPTY as the winner of a draw.
+	// It is called once the turn has passed to the player after the one who made the deciding move
+	Outcome(game *Game) (Piece, []int)
+}
+
+var variants = map[Variant]Rules{
+	Classic:   classicRules{},
+	Misere:    misereRules{},
+	Notakto:   notaktoRules{},
+	Wild:      wildRules{},
+	Numerical: numericalRules{},
+	Ultimate:  ultimateRules{},
+}
+
+// RulesFor looks up the rules of a variant, where no variant at all means classic
+func RulesFor(variant Variant) (Rules, error) {
+	if variant == "" {
+		return classicRules{}, nil
+	}
+	rules, ok := variants[variant]
+	if !ok {
+		return nil, &UnknownVariantError{variant: variant}
+	}
+	return rules, nil
+}
+
+func NewVariantGame(variant Variant, playerX string, playerO string, rows int, columns int, winLength int) (*Game, error) {
+	if variant == "" {
+		variant = Classic
+	}
+	rules, err := RulesFor(variant)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := rules.NewGame(playerX, playerO, rows, columns, winLength)
+	if err != nil {
+		return nil, err
+	}
+	g.Variant = variant
+	return g, nil
+}
+
+// rules falls back to the classic rules for games stored before there were variants
+func (game *Game) rules() Rules {
+	if rules, ok := variants[game.Variant]; ok {
+		return rules
+	}
+	return classicRules{}
+}
+
+func valueOrDefault(value int, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// checkFixedSize returns an error if a size other than the one a variant is always played at is asked for
+func checkFixedSize(rows int, columns int, winLength int, fixedRows int, fixedColumns int, fixedWinLength int) error {
+	if valueOrDefault(rows, fixedRows) != fixedRows ||
+		valueOrDefault(columns, fixedColumns) != fixedColumns ||
+		valueOrDefault(winLength, fixedWinLength) != fixedWinLength {
+		return &InvalidBoardSizeError{
+			rows:      rows,
+			columns:   columns,
+			winLength: winLength,
+		}
+	}
+	return nil
+}
+
+func (game *Game) pieceAt(square int) Piece {
+	return game.Board[square/game.Columns()][square%game.Columns()]
+}
+
+func (game *Game) place(square int, piece Piece) {
+	game.Board[square/game.Columns()][square%game.Columns()] = piece
+}
+
+func (game *Game) emptySquares() []int {
+	var squares []int
+	for square := 0; square < game.Rows()*game.Columns(); square++ {
+		if game.pieceAt(square) == EMPTY {
+			squares = append(squares, square)
+		}
+	}
+	return squares
+}
+
+// classicRules is the game everyone knows: the first to get WinLength of their own pieces in a row wins
+type classicRules struct{}
+
+func (classicRules) NewGame(playerX string, playerO string, rows int, columns int, winLength int) (*Game, error) {
+	return NewGameWithSize(
+		playerX,
+		playerO,
+		valueOrDefault(rows, DefaultBoardSize),
+		valueOrDefault(columns, DefaultBoardSize),
+		valueOrDefault(winLength, DefaultWinLength),
+	)
+}
+
+func (classicRules) LegalMoves(game *Game) []int {
+	return game.emptySquares()
+}
+
+func (classicRules) ApplyMove(game *Game, move int) (int, Piece) {
+	game.place(move, game.CurrentTurn)
+	return move, game.CurrentTurn
+}
+
+func (classicRules) IsTerminal(game *Game) bool {
+	return game.findWinningLine() != nil || game.isBoardFull()
+}
+
+func (classicRules) Outcome(game *Game) (Piece, []int) {
+	line := game.findWinningLine()
+	if line == nil {
+		return EMPTY, nil
+	}
+	return game.pieceAt(line[0]), line
+}
+
+// misereRules are the classic rules turned around, so getting WinLength in a row loses
+type misereRules struct {
+	classicRules
+}
+
+func (misereRules) Outcome(game *Game) (Piece, []int) {
+	line := game.findWinningLine()
+	if line == nil {
+		return EMPTY, nil
+	}
+	return opponentOf(game.pieceAt(line[0])), line
+}
+
+// notaktoRules have both players placing X, and whoever completes a line loses
+type notaktoRules struct {
+	classicRules
+}
+
+func (notaktoRules) ApplyMove(game *Game, move int) (int, Piece) {
+	game.place(move, X)
+	return move, X
+}
+
+func (notaktoRules) Outcome(game *Game) (Piece, []int) {
+	line := game.findWinningLine()
+	if line == nil {
+		return EMPTY, nil
+	}
+	// The player who completed the line has just moved, so the win goes to the player whose turn it is now
+	return game.CurrentTurn, line
+}
+
+// wildRules let either player place either piece, and whoever completes a line of either wins
+type wildRules struct {
+	classicRules
+}
+
+// WildMove encodes a move in wild tic-tac-toe as the square played and the piece placed there
+func WildMove(square int, piece Piece) int {
+	if piece == O {
+		return square*2 + 1
+	}
+	return square * 2
+}
+
+func (wildRules) LegalMoves(game *Game) []int {
+	var moves []int
+	for _, square := range game.emptySquares() {
+		moves = append(moves, WildMove(square, X), WildMove(square, O))
+	}
+	return moves
+}
+
+func (wildRules) ApplyMove(game *Game, move int) (int, Piece) {
+	piece := X
+	if move%2 == 1 {
+		piece = O
+	}
+	game.place(move/2, piece)
+	return move / 2, piece
+}
+
+func (wildRules) Outcome(game *Game) (Piece, []int) {
+	line := game.findWinningLine()
+	if line == nil {
+		return EMPTY, nil
+	}
+	return opponentOf(game.CurrentTurn), line
+}
@@ -0,0 +1,275 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// playMoves plays the moves in order, alternating between the players starting with X
+func playMoves(t *testing.T, game *Game, moves ...int) {
+	for i, move := range moves {
+		player := game.PlayerX
+		if i%2 == 1 {
+			player = game.PlayerO
+		}
+		assert.Equal(t, nil, game.MakeMove(player, move))
+	}
+}
+
+func TestGame_RulesFor(t *testing.T) {
+	tests := []struct {
+		variant  Variant
+		expected Rules
+	}{
+		{variant: "", expected: classicRules{}},
+		{variant: Classic, expected: classicRules{}},
+		{variant: Misere, expected: misereRules{}},
+		{variant: Notakto, expected: notaktoRules{}},
+		{variant: Wild, expected: wildRules{}},
+		{variant: Numerical, expected: numericalRules{}},
+		{variant: Ultimate, expected: ultimateRules{}},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.variant), func(t *testing.T) {
+			rules, err := RulesFor(test.variant)
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expected, rules)
+		})
+	}
+}
+
+func TestGame_RulesFor_Unknown(t *testing.T) {
+	_, err := RulesFor("cubic")
+
+	assert.Equal(t, &UnknownVariantError{variant: "cubic"}, err)
+}
+
+func TestGame_NewVariantGame_Classic(t *testing.T) {
+	game, err := NewVariantGame("", "playerX", "playerO", 0, 0, 0)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, NewGame("playerX", "playerO"), game)
+}
+
+func TestGame_NewVariantGame_WithSize(t *testing.T) {
+	game, err := NewVariantGame(Misere, "playerX", "playerO", 4, 5, 4)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Misere, game.Variant)
+	assert.Equal(t, 4, game.Rows())
+	assert.Equal(t, 5, game.Columns())
+	assert.Equal(t, 4, game.WinLength)
+}
+
+func TestGame_NewVariantGame_InvalidBoardSize(t *testing.T) {
+	_, err := NewVariantGame(Wild, "playerX", "playerO", 2, 0, 0)
+
+	assert.Equal(t, &InvalidBoardSizeError{rows: 2, columns: 3, winLength: 3}, err)
+}
+
+func TestGame_NewVariantGame_Unknown(t *testing.T) {
+	_, err := NewVariantGame("cubic", "playerX", "playerO", 0, 0, 0)
+
+	assert.Equal(t, &UnknownVariantError{variant: "cubic"}, err)
+}
+
+func TestGame_MakeMove_UnknownVariantPlaysClassic(t *testing.T) {
+	game := NewGame("playerX", "playerO")
+	game.Variant = ""
+
+	playMoves(t, game, 0, 3, 1, 4, 2)
+
+	assert.Equal(t, XWon, game.Status)
+	assert.Equal(t, []int{0, 1, 2}, game.WinningLine)
+}
+
+func TestGame_MakeMove_Misere_LineLoses(t *testing.T) {
+	game, _ := NewVariantGame(Misere, "playerX", "playerO", 0, 0, 0)
+
+	playMoves(t, game, 0, 3, 1, 4, 2)
+
+	assert.Equal(t, OWon, game.Status)
+	assert.Equal(t, O, game.Winner)
+	assert.Equal(t, []int{0, 1, 2}, game.WinningLine)
+	assert.Equal(t, true, game.IsFinished())
+}
+
+func TestGame_MakeMove_Misere_Draw(t *testing.T) {
+	game, _ := NewVariantGame(Misere, "playerX", "playerO", 0, 0, 0)
+
+	playMoves(t, game, 4, 0, 2, 6, 3, 5, 7, 1, 8)
+
+	assert.Equal(t, Draw, game.Status)
+	assert.Equal(t, Piece(""), game.Winner)
+}
+
+func TestGame_MakeMove_Notakto_BothPlayersPlaceX(t *testing.T) {
+	game, _ := NewVariantGame(Notakto, "playerX", "playerO", 0, 0, 0)
+
+	playMoves(t, game, 0, 4)
+
+	assert.Equal(t, X, game.Board[0][0])
+	assert.Equal(t, X, game.Board[1][1])
+	assert.Equal(t, X, game.CurrentTurn)
+	assert.Equal(t, X, game.Moves[1].Piece)
+	assert.Equal(t, "playerO", game.Moves[1].Player)
+}
+
+func TestGame_MakeMove_Notakto_LineLoses(t *testing.T) {
+	tests := []struct {
+		name     string
+		moves    []int
+		expected Status
+	}{
+		{name: "completed by X", moves: []int{0, 5, 1, 7, 2}, expected: OWon},
+		{name: "completed by O", moves: []int{0, 5, 1, 2}, expected: XWon},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, _ := NewVariantGame(Notakto, "playerX", "playerO", 0, 0, 0)
+
+			playMoves(t, game, test.moves...)
+
+			assert.Equal(t, test.expected, game.Status)
+			assert.Equal(t, []int{0, 1, 2}, game.WinningLine)
+		})
+	}
+}
+
+func TestGame_LegalMoves_Wild(t *testing.T) {
+	game, _ := NewVariantGame(Wild, "playerX", "playerO", 0, 0, 0)
+	game.Board = [][]Piece{
+		{X, O, X},
+		{EMPTY, O, X},
+		{O, X, O},
+	}
+
+	assert.Equal(t, []int{WildMove(3, X), WildMove(3, O)}, game.LegalMoves())
+}
+
+func TestGame_MakeMove_Wild_EitherPiece(t *testing.T) {
+	game, _ := NewVariantGame(Wild, "playerX", "playerO", 0, 0, 0)
+
+	playMoves(t, game, WildMove(4, O), WildMove(0, X))
+
+	assert.Equal(t, O, game.Board[1][1])
+	assert.Equal(t, X, game.Board[0][0])
+	assert.Equal(t, Move{Player: "playerX", Move: WildMove(4, O), Square: 4, Piece: O, Timestamp: testTime}, game.Moves[0])
+	assert.Equal(t, X, game.CurrentTurn)
+}
+
+func TestGame_MakeMove_Wild_LineOfEitherPieceWins(t *testing.T) {
+	game, _ := NewVariantGame(Wild, "playerX", "playerO", 0, 0, 0)
+
+	playMoves(t, game, WildMove(0, O), WildMove(8, X), WildMove(1, O), WildMove(6, X), WildMove(4, X), WildMove(2, O))
+
+	assert.Equal(t, OWon, game.Status)
+	assert.Equal(t, O, game.Winner)
+	assert.Equal(t, []int{0, 1, 2}, game.WinningLine)
+}
+
+func TestGame_MakeMove_Wild_InvalidMove(t *testing.T) {
+	game, _ := NewVariantGame(Wild, "playerX", "playerO", 0, 0, 0)
+	playMoves(t, game, WildMove(4, O))
+
+	err := game.MakeMove("playerO", WildMove(4, X))
+
+	assert.Equal(t, &InvalidMoveError{move: WildMove(4, X)}, err)
+}
+
+func TestGame_NewVariantGame_Numerical(t *testing.T) {
+	game, err := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, game.Rows())
+
+	_, err = NewVariantGame(Numerical, "playerX", "playerO", 4, 4, 3)
+	assert.Equal(t, &InvalidBoardSizeError{rows: 4, columns: 4, winLength: 3}, err)
+}
+
+func TestGame_LegalMoves_Numerical(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+	game.Board = [][]Piece{
+		{"1", "2", "3"},
+		{"4", EMPTY, "6"},
+		{"7", "8", EMPTY},
+	}
+
+	assert.Equal(t, []int{NumericalMove(4, 5), NumericalMove(4, 9), NumericalMove(8, 5), NumericalMove(8, 9)}, game.LegalMoves())
+
+	game.CurrentTurn = O
+	assert.Equal(t, []int(nil), game.rules().LegalMoves(game))
+}
+
+func TestGame_MakeMove_Numerical(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+
+	playMoves(t, game, NumericalMove(4, 5), NumericalMove(0, 8))
+
+	assert.Equal(t, Piece("5"), game.Board[1][1])
+	assert.Equal(t, Piece("8"), game.Board[0][0])
+	assert.Equal(t, Move{Player: "playerX", Move: NumericalMove(4, 5), Square: 4, Piece: "5", Timestamp: testTime}, game.Moves[0])
+}
+
+func TestGame_MakeMove_Numerical_WrongParity(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+
+	err := game.MakeMove("playerX", NumericalMove(4, 2))
+
+	assert.Equal(t, &InvalidMoveError{move: NumericalMove(4, 2)}, err)
+}
+
+func TestGame_MakeMove_Numerical_NumberAlreadyUsed(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+	playMoves(t, game, NumericalMove(4, 5), NumericalMove(0, 8))
+
+	err := game.MakeMove("playerX", NumericalMove(1, 5))
+
+	assert.Equal(t, &InvalidMoveError{move: NumericalMove(1, 5)}, err)
+}
+
+func TestGame_MakeMove_Numerical_FifteenWins(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+
+	// 8 + 6 + 1 = 15 along the top row, completed by X
+	playMoves(t, game, NumericalMove(8, 9), NumericalMove(0, 8), NumericalMove(4, 3), NumericalMove(1, 6), NumericalMove(2, 1))
+
+	assert.Equal(t, XWon, game.Status)
+	assert.Equal(t, X, game.Winner)
+	assert.Equal(t, []int{0, 1, 2}, game.WinningLine)
+}
+
+func TestGame_MakeMove_Numerical_FullLineNotFifteen(t *testing.T) {
+	game, _ := NewVariantGame(Numerical, "playerX", "playerO", 0, 0, 0)
+
+	playMoves(t, game, NumericalMove(0, 1), NumericalMove(1, 2), NumericalMove(2, 3))
+
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_Rematch_KeepsVariant(t *testing.T) {
+	game, _ := NewVariantGame(Notakto, "playerX", "playerO", 4, 4, 3)
+	_ = game.Resign("playerX")
+
+	rematch, err := game.Rematch("playerX")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Notakto, rematch.Variant)
+	assert.Equal(t, 4, rematch.Rows())
+}
+
+func TestGame_ReplayTo_Variant(t *testing.T) {
+	game, _ := NewVariantGame(Wild, "playerX", "playerO", 0, 0, 0)
+	playMoves(t, game, WildMove(0, O), WildMove(8, X), WildMove(1, O))
+
+	replay, err := game.ReplayTo(2)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, [][]Piece{
+		{O, EMPTY, EMPTY},
+		{EMPTY, EMPTY, EMPTY},
+		{EMPTY, EMPTY, X},
+	}, replay.Board)
+}
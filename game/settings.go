@@ -0,0 +1,32 @@
+package game
+
+// Settings are the options a game is created with, kept apart from the game itself so that it can be created later,
+// e.g. once an invitation to play it has been accepted
+type Settings struct {
+	Variant      Variant
+	Rows         int
+	Columns      int
+	WinLength    int
+	IsPublic     bool
+	HintsEnabled bool
+	// TimeControl starts the clock when the game is created, if set
+	TimeControl *TimeControl
+}
+
+// NewGame creates a game between the players with the settings, checking they are valid for the variant
+func (settings Settings) NewGame(playerX string, playerO string) (*Game, error) {
+	g, err := NewVariantGame(settings.Variant, playerX, playerO, settings.Rows, settings.Columns, settings.WinLength)
+	if err != nil {
+		return nil, err
+	}
+	g.IsPublic = settings.IsPublic
+	g.HintsEnabled = settings.HintsEnabled
+
+	if settings.TimeControl != nil {
+		if err = g.StartClock(*settings.TimeControl); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
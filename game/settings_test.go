@@ -0,0 +1,70 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSettings_NewGame(t *testing.T) {
+	settings := Settings{
+		Variant:      Misere,
+		Rows:         4,
+		Columns:      5,
+		WinLength:    4,
+		IsPublic:     true,
+		HintsEnabled: true,
+		TimeControl:  &TimeControl{Type: PerMove, Limit: 30 * time.Second},
+	}
+
+	game, err := settings.NewGame("playerX", "playerO")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Misere, game.Variant)
+	assert.Equal(t, 4, game.Rows())
+	assert.Equal(t, 5, game.Columns())
+	assert.Equal(t, 4, game.winLength())
+	assert.Equal(t, "playerX", game.PlayerX)
+	assert.Equal(t, "playerO", game.PlayerO)
+	assert.Equal(t, true, game.IsPublic)
+	assert.Equal(t, true, game.HintsEnabled)
+	assert.Equal(t, *settings.TimeControl, game.TimeControl)
+	assert.Equal(t, testTime.Add(30*time.Second).UnixNano(), game.Deadline)
+}
+
+func TestSettings_NewGame_Defaults(t *testing.T) {
+	game, err := Settings{}.NewGame("playerX", "playerO")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Classic, game.Variant)
+	assert.Equal(t, DefaultBoardSize, game.Rows())
+	assert.Equal(t, TimeControl{}, game.TimeControl)
+}
+
+func TestSettings_NewGame_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		expected error
+	}{
+		{
+			name:     "unknown variant",
+			settings: Settings{Variant: "cubic"},
+			expected: &UnknownVariantError{variant: "cubic"},
+		},
+		{
+			name:     "invalid time control",
+			settings: Settings{TimeControl: &TimeControl{Type: PerMove}},
+			expected: &InvalidTimeControlError{timeControl: TimeControl{Type: PerMove}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := test.settings.NewGame("playerX", "playerO")
+
+			assert.Nil(t, game)
+			assert.Equal(t, test.expected, err)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package game
+
+import (
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"hash/fnv"
+	"strconv"
+)
+
+// Symmetry is one of the eight ways a square board can be rotated or reflected onto itself. Rotations are clockwise
+type Symmetry int
+
+const (
+	Identity Symmetry = iota
+	Rotate90
+	Rotate180
+	Rotate270
+	// ReflectHorizontal swaps the left and right of the board
+	ReflectHorizontal
+	// ReflectVertical swaps the top and bottom of the board
+	ReflectVertical
+	// ReflectDiagonal swaps the board across the diagonal from the top left to the bottom right
+	ReflectDiagonal
+	// ReflectAntiDiagonal swaps the board across the diagonal from the top right to the bottom left
+	ReflectAntiDiagonal
+)
+
+var Symmetries = []Symmetry{
+	Identity,
+	Rotate90,
+	Rotate180,
+	Rotate270,
+	ReflectHorizontal,
+	ReflectVertical,
+	ReflectDiagonal,
+	ReflectAntiDiagonal,
+}
+
+type NotSquareError struct {
+	rows    int
+	columns int
+}
+
+func (e *NotSquareError) Error() string {
+	return fmt.Sprintf("a %dx%d board is not square", e.rows, e.columns)
+}
+
+func (e *NotSquareError) Code() string {
+	return "NOT_SQUARE"
+}
+
+func (e *NotSquareError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+// Inverse is the symmetry that undoes this one
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	default:
+		return s
+	}
+}
+
+// keepsShape reports whether the symmetry takes any rectangular board onto itself, rather than only square ones
+func (s Symmetry) keepsShape() bool {
+	return s == Identity || s == Rotate180 || s == ReflectHorizontal || s == ReflectVertical
+}
+
+// transform finds where a square ends up on a board of the given size
+func (s Symmetry) transform(row int, column int, rows int, columns int) (int, int) {
+	switch s {
+	case Rotate90:
+		return column, rows - 1 - row
+	case Rotate180:
+		return rows - 1 - row, columns - 1 - column
+	case Rotate270:
+		return columns - 1 - column, row
+	case ReflectHorizontal:
+		return row, columns - 1 - column
+	case ReflectVertical:
+		return rows - 1 - row, column
+	case ReflectDiagonal:
+		return column, row
+	case ReflectAntiDiagonal:
+		return columns - 1 - column, rows - 1 - row
+	default:
+		return row, column
+	}
+}
+
+// MapSquare finds where a square, numbered from the top left, ends up on a square board with the given number of rows
+func (s Symmetry) MapSquare(square int, size int) int {
+	row, column := s.transform(square/size, square%size, size, size)
+	return row*size + column
+}
+
+// Apply returns a copy of a square board rotated or reflected by the symmetry
+func (s Symmetry) Apply(board [][]Piece) [][]Piece {
+	size := len(board)
+	transformed := newBoard(size, size)
+	for row := range board {
+		for column, piece := range board[row] {
+			r, c := s.transform(row, column, size, size)
+			transformed[r][c] = piece
+		}
+	}
+	return transformed
+}
+
+// CanonicalBoard picks one board to stand for all of a square board's rotations and reflections, and returns it along
+// with the symmetry that takes the board to it. Squares and moves can be mapped onto the canonical board with the
+// symmetry's MapSquare, and back again with its Inverse
+func CanonicalBoard(board [][]Piece) ([][]Piece, Symmetry, error) {
+	if err := checkSquare(board); err != nil {
+		return nil, Identity, err
+	}
+
+	canonical, canonicalSymmetry, canonicalKey := board, Identity, ""
+	for i, symmetry := range Symmetries {
+		transformed := symmetry.Apply(board)
+		if key := boardString(transformed); i == 0 || key < canonicalKey {
+			canonical, canonicalSymmetry, canonicalKey = transformed, symmetry, key
+		}
+	}
+	return canonical, canonicalSymmetry, nil
+}
+
+// BoardHash is the same for a square board and all of its rotations and reflections, and doesn't change between runs
+func BoardHash(board [][]Piece) (uint64, error) {
+	canonical, _, err := CanonicalBoard(board)
+	if err != nil {
+		return 0, err
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(strconv.Itoa(len(canonical)) + ":" + boardString(canonical)))
+	return hash.Sum64(), nil
+}
+
+func checkSquare(board [][]Piece) error {
+	for _, row := range board {
+		if len(row) != len(board) {
+			return &NotSquareError{
+				rows:    len(board),
+				columns: len(row),
+			}
+		}
+	}
+	return nil
+}
+
+// boardString writes the board out row by row, with every piece followed by a comma
+func boardString(board [][]Piece) string {
+	var key []byte
+	for _, row := range board {
+		for _, piece := range row {
+			key = append(key, piece...)
+			key = append(key, ',')
+		}
+	}
+	return string(key)
+}
@@ -0,0 +1,185 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var asymmetricBoard = [][]Piece{
+	{X, O, EMPTY},
+	{EMPTY, X, EMPTY},
+	{EMPTY, EMPTY, O},
+}
+
+func TestSymmetry_Apply(t *testing.T) {
+	tests := []struct {
+		symmetry Symmetry
+		expected [][]Piece
+	}{
+		{symmetry: Identity, expected: [][]Piece{{X, O, EMPTY}, {EMPTY, X, EMPTY}, {EMPTY, EMPTY, O}}},
+		{symmetry: Rotate90, expected: [][]Piece{{EMPTY, EMPTY, X}, {EMPTY, X, O}, {O, EMPTY, EMPTY}}},
+		{symmetry: Rotate180, expected: [][]Piece{{O, EMPTY, EMPTY}, {EMPTY, X, EMPTY}, {EMPTY, O, X}}},
+		{symmetry: Rotate270, expected: [][]Piece{{EMPTY, EMPTY, O}, {O, X, EMPTY}, {X, EMPTY, EMPTY}}},
+		{symmetry: ReflectHorizontal, expected: [][]Piece{{EMPTY, O, X}, {EMPTY, X, EMPTY}, {O, EMPTY, EMPTY}}},
+		{symmetry: ReflectVertical, expected: [][]Piece{{EMPTY, EMPTY, O}, {EMPTY, X, EMPTY}, {X, O, EMPTY}}},
+		{symmetry: ReflectDiagonal, expected: [][]Piece{{X, EMPTY, EMPTY}, {O, X, EMPTY}, {EMPTY, EMPTY, O}}},
+		{symmetry: ReflectAntiDiagonal, expected: [][]Piece{{O, EMPTY, EMPTY}, {EMPTY, X, O}, {EMPTY, EMPTY, X}}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.symmetry.Apply(asymmetricBoard))
+	}
+}
+
+func TestSymmetry_Apply_DoesNotChangeBoard(t *testing.T) {
+	board := NewGame("playerX", "playerO").Board
+	board[0][0] = X
+
+	_ = Rotate90.Apply(board)
+
+	assert.Equal(t, X, board[0][0])
+}
+
+func TestSymmetry_MapSquare_MatchesApply(t *testing.T) {
+	for _, size := range []int{3, 4, 5} {
+		g, _ := NewGameWithSize("playerX", "playerO", size, size, 3)
+		for square := 0; square < size*size; square++ {
+			g.Board[square/size][square%size] = Piece(rune('a' + square))
+		}
+
+		for _, symmetry := range Symmetries {
+			transformed := symmetry.Apply(g.Board)
+			for square := 0; square < size*size; square++ {
+				mapped := symmetry.MapSquare(square, size)
+				assert.Equal(t, g.Board[square/size][square%size], transformed[mapped/size][mapped%size])
+			}
+		}
+	}
+}
+
+func TestSymmetry_Inverse(t *testing.T) {
+	for _, size := range []int{3, 4, 5} {
+		for _, symmetry := range Symmetries {
+			for square := 0; square < size*size; square++ {
+				assert.Equal(t, square, symmetry.Inverse().MapSquare(symmetry.MapSquare(square, size), size))
+			}
+		}
+	}
+}
+
+func TestCanonicalBoard_SameForEverySymmetry(t *testing.T) {
+	expected, _, _ := CanonicalBoard(asymmetricBoard)
+
+	for _, symmetry := range Symmetries {
+		canonical, _, err := CanonicalBoard(symmetry.Apply(asymmetricBoard))
+
+		assert.Equal(t, nil, err)
+		assert.Equal(t, expected, canonical)
+	}
+}
+
+func TestCanonicalBoard_ReturnsSymmetry(t *testing.T) {
+	for _, symmetry := range Symmetries {
+		board := symmetry.Apply(asymmetricBoard)
+
+		canonical, toCanonical, _ := CanonicalBoard(board)
+
+		assert.Equal(t, canonical, toCanonical.Apply(board))
+	}
+}
+
+func TestCanonicalBoard_MapsMoves(t *testing.T) {
+	board := [][]Piece{
+		{EMPTY, EMPTY, EMPTY},
+		{EMPTY, EMPTY, EMPTY},
+		{EMPTY, EMPTY, X},
+	}
+
+	canonical, toCanonical, _ := CanonicalBoard(board)
+
+	// The X is moved to the top left corner, the first square written out, since X sorts before EMPTY
+	assert.Equal(t, X, canonical[0][0])
+	assert.Equal(t, 0, toCanonical.MapSquare(8, 3))
+	assert.Equal(t, 8, toCanonical.Inverse().MapSquare(0, 3))
+	assert.Equal(t, 6, toCanonical.Inverse().MapSquare(toCanonical.MapSquare(6, 3), 3))
+}
+
+func TestCanonicalBoard_LargerBoards(t *testing.T) {
+	for _, size := range []int{4, 5, 19} {
+		g, _ := NewGameWithSize("playerX", "playerO", size, size, 3)
+		g.Board[0][1] = X
+		g.Board[size-1][2] = O
+		expected, _, _ := CanonicalBoard(g.Board)
+
+		for _, symmetry := range Symmetries {
+			canonical, _, err := CanonicalBoard(symmetry.Apply(g.Board))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, expected, canonical)
+		}
+	}
+}
+
+func TestCanonicalBoard_NotSquare(t *testing.T) {
+	g, _ := NewGameWithSize("playerX", "playerO", 3, 4, 3)
+
+	_, _, err := CanonicalBoard(g.Board)
+
+	assert.Equal(t, &NotSquareError{rows: 3, columns: 4}, err)
+}
+
+func TestCanonicalBoard_CountsPositions(t *testing.T) {
+	// Every position reachable in tic-tac-toe, found by playing on from each one not already seen in some other
+	// rotation or reflection
+	positions := map[string]bool{}
+	var playOut func(g *Game)
+	playOut = func(g *Game) {
+		canonical, _, _ := CanonicalBoard(g.Board)
+		if positions[boardString(canonical)] {
+			return
+		}
+		positions[boardString(canonical)] = true
+		for _, move := range g.LegalMoves() {
+			next := g.Clone()
+			_ = next.MakeMove(next.CurrentPlayer(), move)
+			playOut(next)
+		}
+	}
+	playOut(NewGame("playerX", "playerO"))
+
+	assert.Equal(t, 765, len(positions))
+}
+
+func TestBoardHash(t *testing.T) {
+	expected, err := BoardHash(asymmetricBoard)
+	assert.Equal(t, nil, err)
+
+	for _, symmetry := range Symmetries {
+		hash, _ := BoardHash(symmetry.Apply(asymmetricBoard))
+		assert.Equal(t, expected, hash)
+	}
+
+	other := Identity.Apply(asymmetricBoard)
+	other[0][2] = O
+	hash, _ := BoardHash(other)
+	assert.NotEqual(t, expected, hash)
+}
+
+func TestBoardHash_Stable(t *testing.T) {
+	hash, _ := BoardHash(NewGame("playerX", "playerO").Board)
+
+	assert.Equal(t, uint64(0x19272d161b66a3b), hash)
+}
+
+func TestBoardHash_DependsOnSize(t *testing.T) {
+	small, _ := BoardHash(newBoard(3, 3))
+	large, _ := BoardHash(newBoard(4, 4))
+
+	assert.NotEqual(t, small, large)
+}
+
+func TestBoardHash_NotSquare(t *testing.T) {
+	_, err := BoardHash(newBoard(3, 4))
+
+	assert.Equal(t, &NotSquareError{rows: 3, columns: 4}, err)
+}
@@ -0,0 +1,142 @@
+package game
+
+// Ultimate tic-tac-toe is played on nine local boards laid out in a 3x3 grid, which together make up a 9x9 Board.
+// Both the local boards and the squares within them are numbered 0-8 from the top left
+const (
+	localBoards    = 9
+	localBoardSize = 3
+)
+
+type ultimateRules struct{}
+
+func NewUltimateGame(playerX string, playerO string) *Game {
+	g := NewGame(playerX, playerO)
+	g.Variant = Ultimate
+	g.Board = newBoard(localBoards, localBoards)
+	g.LocalStatuses = newLocalStatuses()
+	return g
+}
+
+func newLocalStatuses() []Status {
+	statuses := make([]Status, localBoards)
+	for i := range statuses {
+		statuses[i] = InProgress
+	}
+	return statuses
+}
+
+// UltimateMove encodes a move in Ultimate tic-tac-toe as the local board played in and the square within it
+func UltimateMove(board int, square int) int {
+	return board*localBoards + square
+}
+
+// ultimateSquare finds where on the 9x9 Board a move in Ultimate tic-tac-toe is played
+func ultimateSquare(move int) (int, int) {
+	board, square := move/localBoards, move%localBoards
+	row := (board/localBoardSize)*localBoardSize + square/localBoardSize
+	column := (board%localBoardSize)*localBoardSize + square%localBoardSize
+	return row, column
+}
+
+// The board is always nine 3x3 boards with three in a row to win, so the size can only be left out
+func (ultimateRules) NewGame(playerX string, playerO string, rows int, columns int, winLength int) (*Game, error) {
+	if err := checkFixedSize(rows, columns, winLength, localBoards, localBoards, localBoardSize); err != nil {
+		return nil, err
+	}
+	return NewUltimateGame(playerX, playerO), nil
+}
+
+// LegalMoves allows a move to any empty square of an undecided local board, as long as it is the board the opponent's
+// last move sent the player to, if they were sent to one
+func (ultimateRules) LegalMoves(game *Game) []int {
+	var moves []int
+	for board, status := range game.LocalStatuses {
+		if status != InProgress || (game.ActiveBoard != nil && *game.ActiveBoard != board) {
+			continue
+		}
+		for square := 0; square < localBoards; square++ {
+			row, column := ultimateSquare(UltimateMove(board, square))
+			if game.Board[row][column] == EMPTY {
+				moves = append(moves, UltimateMove(board, square))
+			}
+		}
+	}
+	return moves
+}
+
+// ApplyMove decides the local board that was played in, and works out which board the opponent has to play in next
+func (ultimateRules) ApplyMove(game *Game, move int) (int, Piece) {
+	board, square := move/localBoards, move%localBoards
+	row, column := ultimateSquare(move)
+	game.Board[row][column] = game.CurrentTurn
+
+	local := game.localBoard(board)
+	if winner, line := (classicRules{}).Outcome(local); line != nil {
+		game.LocalStatuses[board] = wonStatus(winner)
+	} else if local.isBoardFull() {
+		game.LocalStatuses[board] = Draw
+	}
+
+	// A player sent to a board that has already been decided may play on any board instead
+	if game.LocalStatuses[square] == InProgress {
+		game.ActiveBoard = &square
+	} else {
+		game.ActiveBoard = nil
+	}
+
+	return row*game.Columns() + column, game.CurrentTurn
+}
+
+func (ultimateRules) IsTerminal(game *Game) bool {
+	if game.globalBoard().findWinningLine() != nil {
+		return true
+	}
+	for _, status := range game.LocalStatuses {
+		if status == InProgress {
+			return false
+		}
+	}
+	return true
+}
+
+// Outcome uses the local boards that make up the winning line as the WinningLine
+func (ultimateRules) Outcome(game *Game) (Piece, []int) {
+	return (classicRules{}).Outcome(game.globalBoard())
+}
+
+// localBoard copies one of the local boards into a classic game, so the classic rules can decide it
+func (game *Game) localBoard(board int) *Game {
+	local := &Game{
+		Board:     newBoard(localBoardSize, localBoardSize),
+		WinLength: localBoardSize,
+	}
+	for square := 0; square < localBoards; square++ {
+		row, column := ultimateSquare(UltimateMove(board, square))
+		local.Board[square/localBoardSize][square%localBoardSize] = game.Board[row][column]
+	}
+	return local
+}
+
+// globalBoard is a classic game with a piece on each local board that has been won
+func (game *Game) globalBoard() *Game {
+	global := &Game{
+		Board:     newBoard(localBoardSize, localBoardSize),
+		WinLength: localBoardSize,
+	}
+	for board, status := range game.LocalStatuses {
+		switch status {
+		case XWon:
+			global.Board[board/localBoardSize][board%localBoardSize] = X
+		case OWon:
+			global.Board[board/localBoardSize][board%localBoardSize] = O
+		}
+	}
+	return global
+}
+
+func wonStatus(winner Piece) Status {
+	if winner == X {
+		return XWon
+	}
+	return OWon
+}
@@ -0,0 +1,308 @@
+package game
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// setLocalBoard fills in one of the local boards of an Ultimate tic-tac-toe game, square by square
+func setLocalBoard(game *Game, board int, pieces [9]Piece) {
+	for square, piece := range pieces {
+		row, column := ultimateSquare(UltimateMove(board, square))
+		game.Board[row][column] = piece
+	}
+}
+
+func intPointer(i int) *int {
+	return &i
+}
+
+func TestGame_NewUltimateGame(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+
+	assert.Equal(t, Ultimate, game.Variant)
+	assert.Equal(t, 9, game.Rows())
+	assert.Equal(t, 9, game.Columns())
+	assert.Equal(t, 3, game.WinLength)
+	assert.Equal(t, X, game.CurrentTurn)
+	assert.Equal(t, InProgress, game.Status)
+	assert.Equal(t, []Status{InProgress, InProgress, InProgress, InProgress, InProgress, InProgress, InProgress, InProgress, InProgress}, game.LocalStatuses)
+	assert.Nil(t, game.ActiveBoard)
+	assert.Equal(t, false, game.IsFinished())
+}
+
+func TestGame_NewVariantGame_Ultimate(t *testing.T) {
+	game, err := NewVariantGame(Ultimate, "playerX", "playerO", 9, 9, 3)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, NewUltimateGame("playerX", "playerO"), game)
+}
+
+func TestGame_NewVariantGame_UltimateWithCustomSize(t *testing.T) {
+	_, err := NewVariantGame(Ultimate, "playerX", "playerO", 3, 3, 3)
+	assert.Equal(t, &InvalidBoardSizeError{rows: 3, columns: 3, winLength: 3}, err)
+
+	_, err = NewVariantGame(Ultimate, "playerX", "playerO", 0, 0, 4)
+	assert.Equal(t, &InvalidBoardSizeError{rows: 0, columns: 0, winLength: 4}, err)
+}
+
+func TestGame_UltimateSquare(t *testing.T) {
+	tests := []struct {
+		board, square, row, column int
+	}{
+		{board: 0, square: 0, row: 0, column: 0},
+		{board: 0, square: 8, row: 2, column: 2},
+		{board: 2, square: 5, row: 1, column: 8},
+		{board: 4, square: 4, row: 4, column: 4},
+		{board: 6, square: 1, row: 6, column: 1},
+		{board: 8, square: 8, row: 8, column: 8},
+	}
+
+	for _, test := range tests {
+		row, column := ultimateSquare(UltimateMove(test.board, test.square))
+
+		assert.Equal(t, test.row, row)
+		assert.Equal(t, test.column, column)
+	}
+}
+
+func TestGame_IsValidMove_Ultimate_AnyBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+
+	assert.Equal(t, true, game.isValidMove(UltimateMove(0, 0)))
+	assert.Equal(t, true, game.isValidMove(UltimateMove(8, 8)))
+}
+
+func TestGame_IsValidMove_Ultimate_OutOfRange(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+
+	assert.Equal(t, false, game.isValidMove(-1))
+	assert.Equal(t, false, game.isValidMove(81))
+}
+
+func TestGame_IsValidMove_Ultimate_WrongBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	game.ActiveBoard = intPointer(4)
+
+	assert.Equal(t, true, game.isValidMove(UltimateMove(4, 0)))
+	assert.Equal(t, false, game.isValidMove(UltimateMove(3, 0)))
+}
+
+func TestGame_IsValidMove_Ultimate_SquareAlreadyOccupied(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	setLocalBoard(game, 4, [9]Piece{X, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY})
+
+	assert.Equal(t, false, game.isValidMove(UltimateMove(4, 0)))
+}
+
+func TestGame_IsValidMove_Ultimate_BoardDecided(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	setLocalBoard(game, 4, [9]Piece{X, X, X, O, O, EMPTY, EMPTY, EMPTY, EMPTY})
+	game.LocalStatuses[4] = XWon
+
+	assert.Equal(t, false, game.isValidMove(UltimateMove(4, 5)))
+}
+
+func TestGame_MakeMove_Ultimate_ValidMove(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+
+	err := game.MakeMove("playerX", UltimateMove(2, 5))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, X, game.Board[1][8])
+	assert.Equal(t, O, game.CurrentTurn)
+	assert.Equal(t, intPointer(5), game.ActiveBoard)
+	assert.Equal(t, InProgress, game.Status)
+	assert.Equal(t, []Move{{Player: "playerX", Move: UltimateMove(2, 5), Square: 1*9 + 8, Piece: X, Timestamp: testTime}}, game.Moves)
+}
+
+func TestGame_MakeMove_Ultimate_WrongBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	_ = game.MakeMove("playerX", UltimateMove(2, 5))
+
+	err := game.MakeMove("playerO", UltimateMove(4, 0))
+
+	assert.Equal(t, &InvalidMoveError{move: UltimateMove(4, 0)}, err)
+	assert.Equal(t, O, game.CurrentTurn)
+}
+
+func TestGame_MakeMove_Ultimate_NotPlayersTurn(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+
+	err := game.MakeMove("playerO", UltimateMove(4, 4))
+
+	assert.Equal(t, &NotPlayersTurnError{player: "playerO"}, err)
+}
+
+func TestGame_MakeMove_Ultimate_WinsLocalBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	setLocalBoard(game, 0, [9]Piece{X, X, EMPTY, O, O, EMPTY, EMPTY, EMPTY, EMPTY})
+
+	err := game.MakeMove("playerX", UltimateMove(0, 2))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, XWon, game.LocalStatuses[0])
+	assert.Equal(t, InProgress, game.Status)
+	assert.Equal(t, intPointer(2), game.ActiveBoard)
+}
+
+func TestGame_MakeMove_Ultimate_DrawsLocalBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	setLocalBoard(game, 0, [9]Piece{X, O, X, X, O, O, O, X, EMPTY})
+
+	err := game.MakeMove("playerX", UltimateMove(0, 8))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Draw, game.LocalStatuses[0])
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_MakeMove_Ultimate_SentToDecidedBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	setLocalBoard(game, 4, [9]Piece{O, O, O, X, X, EMPTY, EMPTY, EMPTY, EMPTY})
+	game.LocalStatuses[4] = OWon
+
+	err := game.MakeMove("playerX", UltimateMove(0, 4))
+
+	assert.Equal(t, nil, err)
+	assert.Nil(t, game.ActiveBoard)
+	assert.Equal(t, nil, game.MakeMove("playerO", UltimateMove(8, 0)))
+}
+
+func TestGame_MakeMove_Ultimate_SentToBoardJustDecided(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	setLocalBoard(game, 2, [9]Piece{X, X, EMPTY, O, O, EMPTY, EMPTY, EMPTY, EMPTY})
+
+	err := game.MakeMove("playerX", UltimateMove(2, 2))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, XWon, game.LocalStatuses[2])
+	assert.Nil(t, game.ActiveBoard)
+}
+
+func TestGame_MakeMove_Ultimate_SetsWinner(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	game.LocalStatuses[0] = XWon
+	game.LocalStatuses[4] = XWon
+	setLocalBoard(game, 8, [9]Piece{X, X, EMPTY, O, O, EMPTY, EMPTY, EMPTY, EMPTY})
+
+	err := game.MakeMove("playerX", UltimateMove(8, 2))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, XWon, game.Status)
+	assert.Equal(t, X, game.Winner)
+	assert.Equal(t, []int{0, 4, 8}, game.WinningLine)
+	assert.Equal(t, true, game.IsFinished())
+	assert.Equal(t, []int{}, game.LegalMoves())
+}
+
+func TestGame_MakeMove_Ultimate_SetsDraw(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	game.LocalStatuses = []Status{XWon, OWon, XWon, XWon, OWon, OWon, OWon, XWon, InProgress}
+	setLocalBoard(game, 8, [9]Piece{X, O, X, X, O, O, O, X, EMPTY})
+
+	err := game.MakeMove("playerX", UltimateMove(8, 8))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Draw, game.LocalStatuses[8])
+	assert.Equal(t, Draw, game.Status)
+	assert.Equal(t, Piece(""), game.Winner)
+	assert.Equal(t, true, game.IsFinished())
+}
+
+func TestGame_MakeMove_Ultimate_LocalDrawsDoNotCountForEitherPlayer(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	game.LocalStatuses[0] = XWon
+	game.LocalStatuses[1] = Draw
+	setLocalBoard(game, 2, [9]Piece{X, X, EMPTY, O, O, EMPTY, EMPTY, EMPTY, EMPTY})
+
+	err := game.MakeMove("playerX", UltimateMove(2, 2))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, InProgress, game.Status)
+}
+
+func TestGame_MakeMove_Ultimate_AfterStatusFinished(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	_ = game.Resign("playerO")
+
+	err := game.MakeMove("playerX", UltimateMove(4, 4))
+
+	assert.IsType(t, &FinishedError{}, err)
+}
+
+func TestGame_IsWinner_Ultimate(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	// Three in a row across local boards doesn't win, only three local boards in a row do
+	setLocalBoard(game, 0, [9]Piece{EMPTY, EMPTY, X, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY})
+	setLocalBoard(game, 1, [9]Piece{X, X, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY, EMPTY})
+
+	isWinner, winner := game.isWinner()
+	assert.Equal(t, false, isWinner)
+	assert.Equal(t, EMPTY, winner)
+
+	game.LocalStatuses[2] = OWon
+	game.LocalStatuses[5] = OWon
+	game.LocalStatuses[8] = OWon
+
+	isWinner, winner = game.isWinner()
+	assert.Equal(t, true, isWinner)
+	assert.Equal(t, O, winner)
+}
+
+func TestGame_LegalMoves_Ultimate_AnyBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+
+	assert.Equal(t, 81, len(game.LegalMoves()))
+}
+
+func TestGame_LegalMoves_Ultimate_ActiveBoard(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	_ = game.MakeMove("playerX", UltimateMove(4, 3))
+
+	assert.Equal(t, []int{27, 28, 29, 30, 31, 32, 33, 34, 35}, game.LegalMoves())
+}
+
+func TestGame_Clone_Ultimate(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	_ = game.MakeMove("playerX", UltimateMove(4, 3))
+
+	clone := game.Clone()
+	clone.LocalStatuses[0] = XWon
+	*clone.ActiveBoard = 5
+
+	assert.Equal(t, InProgress, game.LocalStatuses[0])
+	assert.Equal(t, intPointer(3), game.ActiveBoard)
+}
+
+func TestGame_ReplayTo_Ultimate(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	moves := []int{UltimateMove(4, 0), UltimateMove(0, 4), UltimateMove(4, 1), UltimateMove(1, 4), UltimateMove(4, 2)}
+	for i, move := range moves {
+		assert.Equal(t, nil, game.MakeMove([]string{"playerX", "playerO"}[i%2], move))
+	}
+	assert.Equal(t, XWon, game.LocalStatuses[4])
+
+	replay, err := game.ReplayTo(len(moves))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, game, replay)
+
+	replay, err = game.ReplayTo(1)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, InProgress, replay.LocalStatuses[4])
+	assert.Equal(t, intPointer(0), replay.ActiveBoard)
+	assert.Equal(t, O, replay.CurrentTurn)
+}
+
+func TestGame_Rematch_Ultimate(t *testing.T) {
+	game := NewUltimateGame("playerX", "playerO")
+	_ = game.Resign("playerO")
+
+	rematch, err := game.Rematch("playerX")
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, Ultimate, rematch.Variant)
+	assert.Equal(t, "playerO", rematch.PlayerX)
+	assert.Equal(t, 9, rematch.Rows())
+	assert.Equal(t, newLocalStatuses(), rematch.LocalStatuses)
+}
@@ -5,7 +5,9 @@ go 1.20
 require (
 	github.com/aws/aws-lambda-go v1.41.0
 	github.com/aws/aws-sdk-go v1.44.275
+	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/google/uuid v1.3.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/sirupsen/logrus v1.9.2
 	github.com/stretchr/testify v1.8.1
 )
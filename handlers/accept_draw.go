@@ -0,0 +1,13 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func (h *Handlers) AcceptDraw(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody GameActionRequest) (events.APIGatewayProxyResponse, error) {
+	return h.applyGameAction(websocketEvent, requestBody, func(g *game.Game, playerId string) error {
+		return g.AcceptDraw(playerId)
+	})
+}
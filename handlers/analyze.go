@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type AnalyzeRequest struct {
+	Id  string `json:"id"`
+	Ply *int   `json:"ply"`
+}
+
+func (r *AnalyzeRequest) Validate() error {
+	return router.Required("id", r.Id)
+}
+
+type AnalyzeResponse struct {
+	Id    string
+	Ply   int
+	Moves []game.MoveAnalysis
+}
+
+func (h *Handlers) Analyze(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody AnalyzeRequest) (events.APIGatewayProxyResponse, error) {
+	gameId := requestBody.Id
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	g, err := h.Games.GetGame(gameId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if !g.CanView(playerId) {
+		log.Infof("Player with ID %s tried to analyse game %s which is private and does not belong to them", playerId, gameId)
+		return utils.ForbiddenResponse(), nil
+	}
+
+	ply := len(g.Moves)
+	if requestBody.Ply != nil {
+		ply = *requestBody.Ply
+	}
+
+	analysis, err := g.AnalyzeAt(ply)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	return utils.OkResponse(AnalyzeResponse{
+		Id:    g.Id,
+		Ply:   ply,
+		Moves: analysis,
+	}), nil
+}
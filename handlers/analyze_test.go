@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_Analyze(t *testing.T) {
+	tests := []struct {
+		name               string
+		connectionId       string
+		gameId             string
+		finished           bool
+		hints              bool
+		ply                string
+		expectedStatusCode int
+		expectedErrorCode  string
+		expectedPly        int
+		expectedMoves      int
+	}{
+		{
+			name:               "resigned game",
+			connectionId:       "playerX",
+			finished:           true,
+			expectedStatusCode: http.StatusOK,
+			expectedPly:        2,
+			expectedMoves:      7,
+		},
+		{
+			name:               "earlier ply of a finished game",
+			connectionId:       "playerO",
+			finished:           true,
+			ply:                `, "ply": 1`,
+			expectedStatusCode: http.StatusOK,
+			expectedPly:        1,
+			expectedMoves:      8,
+		},
+		{
+			name:               "in-progress game with hints",
+			connectionId:       "playerX",
+			hints:              true,
+			expectedStatusCode: http.StatusOK,
+			expectedPly:        2,
+			expectedMoves:      7,
+		},
+		{
+			name:               "in-progress game without hints",
+			connectionId:       "playerX",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedErrorCode:  "HINTS_DISABLED",
+		},
+		{
+			name:               "invalid ply",
+			connectionId:       "playerX",
+			finished:           true,
+			ply:                `, "ply": 3`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedErrorCode:  "INVALID_PLY",
+		},
+		{
+			name:               "not a player",
+			connectionId:       "someOtherPlayer",
+			finished:           true,
+			expectedStatusCode: http.StatusForbidden,
+			expectedErrorCode:  "FORBIDDEN",
+		},
+		{
+			name:               "game does not exist",
+			connectionId:       "playerX",
+			gameId:             "does-not-exist",
+			expectedStatusCode: http.StatusNotFound,
+			expectedErrorCode:  "ENTITY_NOT_FOUND",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, _ := newTestHandlers()
+			newGame := game.NewGame("playerX", "playerO")
+			newGame.HintsEnabled = test.hints
+			_ = newGame.MakeMove("playerX", 4)
+			_ = newGame.MakeMove("playerO", 0)
+			if test.finished {
+				_ = newGame.Resign("playerO")
+			}
+			g, _ := h.Games.CreateGame(*newGame)
+
+			gameId := g.Id
+			if test.gameId != "" {
+				gameId = test.gameId
+			}
+
+			body := fmt.Sprintf(`{"id": "%s"%s}`, gameId, test.ply)
+			response, err := router.Decode(h.Analyze)(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			if test.expectedStatusCode == http.StatusOK {
+				var analysis AnalyzeResponse
+				unmarshalPayload(t, response, &analysis)
+				assert.Equal(t, g.Id, analysis.Id)
+				assert.Equal(t, test.expectedPly, analysis.Ply)
+				assert.Equal(t, test.expectedMoves, len(analysis.Moves))
+			} else {
+				assert.Contains(t, response.Body, test.expectedErrorCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func (h *Handlers) CancelMatch(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	if _, err := h.MatchQueue.Remove(playerId); err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	return utils.OkResponse(utils.Message{Message: "You have left the match queue"}), nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+// Connect registers the connection for the player the $connect authorizer verified it for, so that messages can be
+// pushed to them and so that reconnecting resumes the same player
+func (h *Handlers) Connect(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	connectionId := websocketEvent.RequestContext.ConnectionID
+
+	playerId := auth.PrincipalId(websocketEvent)
+	if playerId == "" {
+		log.Infof("Connection with ID %s was not authorized", connectionId)
+		return utils.UnauthorizedResponse(), nil
+	}
+	message := fmt.Sprintf("Connection ID: %s, Player ID: %s", connectionId, playerId)
+
+	if _, err := h.Connections.CreateConnection(connectionId, playerId); err != nil {
+		log.Errorf("An error occurred while creating connection with ID %s - %s", connectionId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	// A player reconnecting while their old connection is still open is already in the lobby, so nobody needs telling
+	displayName := getDisplayName(websocketEvent, playerId)
+	previous, err := h.Players.GetPlayer(playerId)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		log.Errorf("An error occurred while getting player with ID %s - %s", playerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+	inLobby := previous.ConnectionId != "" && previous.DisplayName == displayName
+
+	if _, err := h.Players.ConnectPlayer(playerId, connectionId, displayName); err != nil {
+		log.Errorf("An error occurred while connecting player with ID %s to connection with ID %s - %s", playerId, connectionId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	if !inLobby {
+		h.sendPresence(websocketEvent, utils.PlayerJoined, LobbyPlayer{Id: playerId, DisplayName: displayName})
+	}
+
+	return utils.OkResponse(utils.Message{Message: message}), nil
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func newConnectEvent(connectionId string, principalId string) events.APIGatewayWebsocketProxyRequest {
+	websocketEvent := newWebsocketEvent(connectionId, "")
+	if principalId != "" {
+		websocketEvent.RequestContext.Authorizer = auth.AuthorizerContext(principalId, nil)
+	}
+	return websocketEvent
+}
+
+func TestHandlers_Connect(t *testing.T) {
+	tests := []struct {
+		name               string
+		principalId        string
+		expectedStatusCode int
+	}{
+		{
+			name:               "authorized",
+			principalId:        "player",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not authorized",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, _ := newTestHandlers()
+
+			response, err := h.Connect(context.Background(), newConnectEvent("connection", test.principalId))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+
+			c, err := h.Connections.GetConnection("connection")
+			if test.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, nil, err)
+				assert.Equal(t, test.principalId, c.PlayerId)
+
+				p, err := h.Players.GetPlayer(c.PlayerId)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, "connection", p.ConnectionId)
+			} else {
+				assert.NotEqual(t, nil, err)
+			}
+		})
+	}
+}
+
+func TestHandlers_Connect_SamePrincipalIsSamePlayer(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	_, _ = h.Connect(context.Background(), newConnectEvent("firstConnection", "player"))
+	_, _ = h.Connect(context.Background(), newConnectEvent("secondConnection", "player"))
+
+	first, _ := h.Connections.GetConnection("firstConnection")
+	second, _ := h.Connections.GetConnection("secondConnection")
+	assert.Equal(t, first.PlayerId, second.PlayerId)
+}
+
+func TestHandlers_PrincipalIsPlayer(t *testing.T) {
+	h, _ := newTestHandlers()
+	websocketEvent := newConnectEvent("unregisteredConnection", "playerX")
+	websocketEvent.Body = `{"playerO": "playerO"}`
+
+	response, err := router.Decode(h.CreateGame)(context.Background(), websocketEvent)
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "playerX", unmarshalGame(t, response).PlayerX)
+}
+
+func TestHandlers_ReconnectAndResumeGame(t *testing.T) {
+	h, messenger := newTestHandlers()
+	playerId := "reconnectingPlayer"
+	_, _ = h.Connect(context.Background(), newConnectEvent("oldConnection", playerId))
+	g, _ := h.Games.CreateGame(*game.NewGame(playerId, "playerO"))
+
+	_, _ = h.Disconnect(context.Background(), newWebsocketEvent("oldConnection", ""))
+
+	// Moves made while a player is offline are not pushed to them
+	_ = g.MakeMove(playerId, 0)
+	g, _ = h.Games.UpdateGame(g)
+	response, _ := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerO", fmt.Sprintf(`{"id": "%s", "move": 4}`, g.Id)))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{}, messenger.recipients())
+
+	_, _ = h.Connect(context.Background(), newConnectEvent("newConnection", playerId))
+
+	response, _ = h.ListGames(context.Background(), newWebsocketEvent("newConnection", ""))
+	assert.Contains(t, response.Body, g.Id)
+
+	response, _ = router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("newConnection", fmt.Sprintf(`{"id": "%s", "move": 8}`, g.Id)))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerO", fmt.Sprintf(`{"id": "%s", "move": 2}`, g.Id)))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{"playerO", "newConnection"}, messenger.recipients())
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+type TimeControlRequest struct {
+	Type             game.TimeControlType `json:"type"`
+	LimitSeconds     int                  `json:"limitSeconds"`
+	IncrementSeconds int                  `json:"incrementSeconds"`
+}
+
+type CreateGameRequest struct {
+	Variant    string `json:"variant"`
+	PlayerO    string `json:"playerO"`
+	Difficulty string `json:"difficulty"`
+	Rows       int    `json:"rows"`
+	Columns    int    `json:"columns"`
+	WinLength  int    `json:"winLength"`
+	IsPublic   bool   `json:"isPublic"`
+	Hints      bool   `json:"hints"`
+	// Open creates an invitation to the game rather than the game itself, for any player with its join code to accept
+	Open bool `json:"open"`
+
+	TimeControl *TimeControlRequest `json:"timeControl"`
+}
+
+// Validate requires an opponent unless the game is against the computer or open to whoever is invited
+func (r *CreateGameRequest) Validate() error {
+	if r.Open || r.Difficulty != "" {
+		return nil
+	}
+	return router.Required("playerO", r.PlayerO)
+}
+
+func (h *Handlers) CreateGame(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody CreateGameRequest) (events.APIGatewayProxyResponse, error) {
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	if requestBody.Open {
+		return h.createOpenGame(websocketEvent, playerId, requestBody)
+	}
+
+	if requestBody.Difficulty != "" {
+		return h.createComputerGame(playerId, requestBody)
+	}
+
+	playerO, err := h.Players.GetPlayer(requestBody.PlayerO)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	newGame, err := newGameFromRequest(playerId, playerO.Id, requestBody)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	g, err := h.Games.CreateGame(*newGame)
+	if err != nil {
+		log.Errorf("An error occurred when creating game - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	if err = h.sendToPlayer(websocketEvent, playerO.Id, utils.GameStarted, g); err != nil {
+		log.Errorf("An error occurred when sending message from %s to %s - %s", playerId, playerO.Id, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(g), nil
+}
+
+func (h *Handlers) createComputerGame(playerId string, requestBody CreateGameRequest) (events.APIGatewayProxyResponse, error) {
+	difficulty, err := engine.ParseDifficulty(requestBody.Difficulty)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	newGame, err := newGameFromRequest(playerId, engine.PlayerId, requestBody)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+	if err = engine.CheckVariant(difficulty, newGame.Variant); err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+	if err = engine.CheckBoardSize(difficulty, newGame.Rows(), newGame.Columns()); err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+	newGame.ComputerDifficulty = string(difficulty)
+
+	g, err := h.Games.CreateGame(*newGame)
+	if err != nil {
+		log.Errorf("An error occurred when creating game - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(g), nil
+}
+
+// createOpenGame checks the game could be created, and invites whoever the player shares the join code with to play it
+func (h *Handlers) createOpenGame(websocketEvent events.APIGatewayWebsocketProxyRequest, playerId string, requestBody CreateGameRequest) (events.APIGatewayProxyResponse, error) {
+	settings := requestBody.settings()
+	if _, err := settings.NewGame(playerId, ""); err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	invitation, err := h.Invitations.CreateInvitation(playerId, getDisplayName(websocketEvent, playerId), settings)
+	if err != nil {
+		log.Errorf("An error occurred when creating invitation - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	// Only invitations to public games are listed in the lobby
+	if settings.IsPublic {
+		h.sendLobbyUpdate(websocketEvent)
+	}
+
+	return utils.OkResponse(invitation), nil
+}
+
+func newGameFromRequest(playerX string, playerO string, requestBody CreateGameRequest) (*game.Game, error) {
+	return requestBody.settings().NewGame(playerX, playerO)
+}
+
+func (r *CreateGameRequest) settings() game.Settings {
+	settings := game.Settings{
+		Variant:      game.Variant(r.Variant),
+		Rows:         r.Rows,
+		Columns:      r.Columns,
+		WinLength:    r.WinLength,
+		IsPublic:     r.IsPublic,
+		HintsEnabled: r.Hints,
+	}
+
+	if tc := r.TimeControl; tc != nil {
+		settings.TimeControl = &game.TimeControl{
+			Type:      tc.Type,
+			Limit:     time.Duration(tc.LimitSeconds) * time.Second,
+			Increment: time.Duration(tc.IncrementSeconds) * time.Second,
+		}
+	}
+
+	return settings
+}
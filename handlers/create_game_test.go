@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHandlers_CreateGame(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectedStatusCode int
+		expectedPlayerO    string
+		expectedRows       int
+		expectedWinLength  int
+		expectedIsPublic   bool
+		expectedRecipients []string
+	}{
+		{
+			name:               "against another player",
+			body:               `{"playerO": "playerO"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    "playerO",
+			expectedRows:       3,
+			expectedWinLength:  3,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "with a custom board size",
+			body:               `{"playerO": "playerO", "rows": 15, "columns": 15, "winLength": 5}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    "playerO",
+			expectedRows:       15,
+			expectedWinLength:  5,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "public game",
+			body:               `{"playerO": "playerO", "isPublic": true}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    "playerO",
+			expectedRows:       3,
+			expectedWinLength:  3,
+			expectedIsPublic:   true,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "with a time control",
+			body:               `{"playerO": "playerO", "timeControl": {"type": "TOTAL", "limitSeconds": 300, "incrementSeconds": 2}}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    "playerO",
+			expectedRows:       3,
+			expectedWinLength:  3,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "invalid time control",
+			body:               `{"playerO": "playerO", "timeControl": {"type": "PER_MOVE", "limitSeconds": 0}}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "no opponent",
+			body:               `{"rows": 4, "columns": 4}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "against the computer",
+			body:               `{"difficulty": "perfect"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    engine.PlayerId,
+			expectedRows:       3,
+			expectedWinLength:  3,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "opponent has never connected",
+			body:               `{"playerO": "unknownPlayer"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "invalid board size",
+			body:               `{"playerO": "playerO", "rows": 2}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "unknown difficulty",
+			body:               `{"difficulty": "impossible"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "ultimate",
+			body:               `{"playerO": "playerO", "variant": "ultimate"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    "playerO",
+			expectedRows:       9,
+			expectedWinLength:  3,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "ultimate against the random computer",
+			body:               `{"variant": "ultimate", "difficulty": "random"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    engine.PlayerId,
+			expectedRows:       9,
+			expectedWinLength:  3,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "ultimate against the perfect computer",
+			body:               `{"variant": "ultimate", "difficulty": "perfect"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "large board against the perfect computer",
+			body:               `{"difficulty": "perfect", "rows": 4, "columns": 4}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "ultimate with a custom board size",
+			body:               `{"playerO": "playerO", "variant": "ultimate", "rows": 15}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "misere with a custom board size",
+			body:               `{"playerO": "playerO", "variant": "misere", "rows": 4, "columns": 4, "winLength": 4}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerO:    "playerO",
+			expectedRows:       4,
+			expectedWinLength:  4,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "numerical against the greedy computer",
+			body:               `{"variant": "numerical", "difficulty": "greedy"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "unknown variant",
+			body:               `{"playerO": "playerO", "variant": "cubic"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "malformed body",
+			body:               `{"playerO": `,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+
+			response, err := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", test.body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, test.expectedRecipients, messenger.recipients())
+
+			if test.expectedStatusCode == http.StatusOK {
+				g := unmarshalGame(t, response)
+				assert.Equal(t, "playerX", g.PlayerX)
+				assert.Equal(t, test.expectedPlayerO, g.PlayerO)
+				assert.Equal(t, test.expectedRows, g.Rows())
+				assert.Equal(t, test.expectedWinLength, g.WinLength)
+				assert.Equal(t, game.InProgress, g.Status)
+				assert.Equal(t, test.expectedIsPublic, g.IsPublic)
+
+				storedGame, err := h.Games.GetGame(g.Id)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, g.PlayerO, storedGame.PlayerO)
+			}
+		})
+	}
+}
+
+func TestHandlers_CreateGame_TimeControl(t *testing.T) {
+	h, _ := newTestHandlers()
+	body := `{"playerO": "playerO", "timeControl": {"type": "TOTAL", "limitSeconds": 300, "incrementSeconds": 2}}`
+
+	response, _ := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", body))
+
+	g := unmarshalGame(t, response)
+	assert.Equal(t, game.TimeControl{Type: game.Total, Limit: 5 * time.Minute, Increment: 2 * time.Second}, g.TimeControl)
+	assert.Equal(t, 5*time.Minute, g.RemainingX)
+	assert.Equal(t, 5*time.Minute, g.RemainingO)
+	assert.NotEqual(t, int64(0), g.Deadline)
+}
+
+func TestHandlers_CreateGame_Variant(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	response, _ := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", `{"playerO": "playerO", "variant": "notakto"}`))
+
+	g := unmarshalGame(t, response)
+	assert.Equal(t, game.Notakto, g.Variant)
+
+	storedGame, _ := h.Games.GetGame(g.Id)
+	assert.Equal(t, game.Notakto, storedGame.Variant)
+}
+
+func TestHandlers_CreateGame_Ultimate(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	response, _ := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", `{"playerO": "playerO", "variant": "ultimate"}`))
+
+	g := unmarshalGame(t, response)
+	assert.Equal(t, game.Ultimate, g.Variant)
+	assert.Equal(t, 9, len(g.LocalStatuses))
+	assert.Nil(t, g.ActiveBoard)
+
+	storedGame, _ := h.Games.GetGame(g.Id)
+	assert.Equal(t, game.Ultimate, storedGame.Variant)
+}
+
+func TestHandlers_CreateGame_Open(t *testing.T) {
+	h, messenger := newTestHandlers()
+	body := `{"open": true, "rows": 4, "columns": 4, "winLength": 3, "isPublic": true}`
+
+	response, err := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", body))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{}, messenger.recipients())
+
+	var invitation db.Invitation
+	unmarshalPayload(t, response, &invitation)
+	assert.Equal(t, 6, len(invitation.Id))
+	assert.Equal(t, "playerX", invitation.PlayerId)
+	assert.Equal(t, "playerX", invitation.DisplayName)
+	assert.Equal(t, game.Settings{Rows: 4, Columns: 4, WinLength: 3, IsPublic: true}, invitation.Settings)
+
+	storedInvitation, err := h.Invitations.GetInvitation(invitation.Id)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, invitation, storedInvitation)
+}
+
+func TestHandlers_CreateGame_OpenInvalidSettings(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	response, _ := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", `{"open": true, "rows": 3, "columns": 3, "winLength": 4}`))
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+func (h *Handlers) Disconnect(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	connectionId := websocketEvent.RequestContext.ConnectionID
+	message := fmt.Sprintf("Goodbye %s", connectionId)
+
+	c, err := h.Connections.DeleteConnection(connectionId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if err := h.Spectators.RemoveSpectatorsForConnection(connectionId); err != nil {
+		log.Errorf("An error occurred while removing spectators for connection with ID %s - %s", connectionId, err)
+	}
+
+	playerId := c.PlayerId
+	if playerId == "" {
+		playerId = c.Id
+	}
+
+	p, err := h.Players.DisconnectPlayer(playerId, connectionId)
+	if err != nil {
+		if !errors.Is(err, errs.ErrNotFound) {
+			log.Errorf("An error occurred while disconnecting player with ID %s - %s", playerId, err)
+			return utils.InternalServerErrorResponse(), nil
+		}
+	}
+
+	// A player who has already reconnected elsewhere can keep their place in the queue, and is still in the lobby
+	if p.ConnectionId == "" {
+		if _, removeErr := h.MatchQueue.Remove(playerId); removeErr != nil {
+			if !errors.Is(removeErr, errs.ErrNotFound) {
+				log.Errorf("An error occurred while removing player with ID %s from the match queue - %s", playerId, removeErr)
+			}
+		}
+
+		// Players who were never connected were never in the lobby either
+		if err == nil {
+			h.sendPresence(websocketEvent, utils.PlayerLeft, LobbyPlayer{Id: playerId, DisplayName: p.DisplayName})
+		}
+	}
+
+	return utils.OkResponse(utils.Message{Message: message}), nil
+}
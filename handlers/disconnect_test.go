@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_Disconnect(t *testing.T) {
+	tests := []struct {
+		name               string
+		connectionId       string
+		expectedStatusCode int
+	}{
+		{
+			name:               "connected",
+			connectionId:       "playerX",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not connected",
+			connectionId:       "unknownConnection",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, _ := newTestHandlers()
+			_, _ = h.MatchQueue.Enqueue("playerX", "3x3x3")
+
+			response, err := h.Disconnect(context.Background(), newWebsocketEvent(test.connectionId, ""))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+
+			_, err = h.Connections.GetConnection(test.connectionId)
+			assert.IsType(t, &db.EntityDoesNotExistError{}, err)
+
+			_, err = h.MatchQueue.Remove(test.connectionId)
+			assert.IsType(t, &db.EntityDoesNotExistError{}, err)
+		})
+	}
+}
+
+func TestHandlers_Disconnect_MarksPlayerOffline(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	_, _ = h.Disconnect(context.Background(), newWebsocketEvent("playerX", ""))
+
+	p, err := h.Players.GetPlayer("playerX")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "", p.ConnectionId)
+}
+
+func TestHandlers_Disconnect_AlreadyReconnected(t *testing.T) {
+	h, _ := newTestHandlers()
+	connect(h, "newConnection", "playerX")
+	_, _ = h.MatchQueue.Enqueue("playerX", "3x3x3")
+
+	_, _ = h.Disconnect(context.Background(), newWebsocketEvent("playerX", ""))
+
+	p, _ := h.Players.GetPlayer("playerX")
+	assert.Equal(t, "newConnection", p.ConnectionId)
+
+	_, err := h.MatchQueue.Remove("playerX")
+	assert.Equal(t, nil, err)
+}
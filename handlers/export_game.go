@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type ExportGameRequest struct {
+	Id string `json:"id"`
+}
+
+func (r *ExportGameRequest) Validate() error {
+	return router.Required("id", r.Id)
+}
+
+type ExportGameResponse struct {
+	Id       string
+	Notation string
+}
+
+func (h *Handlers) ExportGame(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody ExportGameRequest) (events.APIGatewayProxyResponse, error) {
+	gameId := requestBody.Id
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	g, err := h.Games.GetGame(gameId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if !g.CanView(playerId) {
+		log.Infof("Player with ID %s tried to export game %s which is private and does not belong to them", playerId, gameId)
+		return utils.ForbiddenResponse(), nil
+	}
+
+	return utils.OkResponse(ExportGameResponse{
+		Id:       g.Id,
+		Notation: g.Notation(),
+	}), nil
+}
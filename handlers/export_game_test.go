@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_ExportGame(t *testing.T) {
+	tests := []struct {
+		name               string
+		connectionId       string
+		gameId             string
+		isPublic           bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "player",
+			connectionId:       "playerO",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not a player",
+			connectionId:       "someOtherPlayer",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "spectator of public game",
+			connectionId:       "someOtherPlayer",
+			isPublic:           true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "game does not exist",
+			connectionId:       "playerX",
+			gameId:             "does-not-exist",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, _ := newTestHandlers()
+			newGame := game.NewGame("playerX", "playerO")
+			newGame.IsPublic = test.isPublic
+			_ = newGame.MakeMove("playerX", 4)
+			g, _ := h.Games.CreateGame(*newGame)
+
+			gameId := g.Id
+			if test.gameId != "" {
+				gameId = test.gameId
+			}
+
+			body := fmt.Sprintf(`{"id": "%s"}`, gameId)
+			response, err := router.Decode(h.ExportGame)(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			if test.expectedStatusCode == http.StatusOK {
+				var export ExportGameResponse
+				unmarshalPayload(t, response, &export)
+				assert.Equal(t, g.Id, export.Id)
+				assert.Equal(t, g.Notation(), export.Notation)
+			}
+		})
+	}
+}
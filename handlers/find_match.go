@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+type FindMatchRequest struct {
+	Variant   string `json:"variant"`
+	Rows      int    `json:"rows"`
+	Columns   int    `json:"columns"`
+	WinLength int    `json:"winLength"`
+}
+
+func (r *FindMatchRequest) settings() game.Settings {
+	return game.Settings{
+		Variant:   game.Variant(r.Variant),
+		Rows:      r.Rows,
+		Columns:   r.Columns,
+		WinLength: r.WinLength,
+	}
+}
+
+// FindMatch pairs the player with someone waiting to play the same variant at the same size, or queues them until
+// someone else looks for the same game
+func (h *Handlers) FindMatch(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody FindMatchRequest) (events.APIGatewayProxyResponse, error) {
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	settings := requestBody.settings()
+	gameType, err := matchGameType(settings)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	opponent, matched, err := h.matchOpponent(playerId, gameType)
+	if err != nil {
+		log.Errorf("An error occurred while finding an opponent for player with ID %s - %s", playerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+	if !matched {
+		return utils.OkResponse(utils.Message{Message: fmt.Sprintf("Waiting for an opponent to play %s", gameType)}), nil
+	}
+
+	// The opponent has been waiting longest, so they get to go first
+	newGame, err := settings.NewGame(opponent.Id, playerId)
+	if err != nil {
+		log.Errorf("An error occurred while creating the game for players with IDs %s and %s - %s", opponent.Id, playerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	g, err := h.Games.CreateGame(*newGame)
+	if err != nil {
+		log.Errorf("An error occurred when creating game - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	if err = h.sendToPlayer(websocketEvent, opponent.Id, utils.GameStarted, g); err != nil {
+		log.Errorf("An error occurred when sending message from %s to %s - %s", playerId, opponent.Id, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(g), nil
+}
+
+// matchGameType checks the settings make a valid game, and names the game they make with the variant and the size
+// it is played at once the variant's defaults have been filled in, so that requests for the same game match however
+// they were written
+func matchGameType(settings game.Settings) (string, error) {
+	g, err := settings.NewGame("", "")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %dx%dx%d", g.Variant, g.Rows(), g.Columns(), g.WinLength), nil
+}
+
+// matchOpponent pairs the player with the longest waiting opponent, or queues them if nobody is waiting. Two players
+// searching at the same moment can both find the queue empty, so once queued the player looks again for anyone who
+// queued before them. Only the second of the two can find the first, so they can't both claim each other
+func (h *Handlers) matchOpponent(playerId string, gameType string) (db.QueueEntry, bool, error) {
+	opponent, err := h.findWaitingOpponent(playerId, gameType, time.Now().UnixNano())
+	if err == nil {
+		// The caller may still be waiting in the queue for a different game
+		if _, err := h.MatchQueue.Remove(playerId); err != nil && !errors.Is(err, errs.ErrNotFound) {
+			log.Errorf("An error occurred while removing player with ID %s from the match queue - %s", playerId, err)
+		}
+		return opponent, true, nil
+	} else if !errors.Is(err, errs.ErrNotFound) {
+		return db.QueueEntry{}, false, err
+	}
+
+	entry, err := h.MatchQueue.Enqueue(playerId, gameType)
+	if err != nil {
+		return db.QueueEntry{}, false, err
+	}
+
+	opponent, err = h.findWaitingOpponent(playerId, gameType, entry.QueuedAt)
+	if errors.Is(err, errs.ErrNotFound) {
+		return db.QueueEntry{}, false, nil
+	} else if err != nil {
+		return db.QueueEntry{}, false, err
+	}
+
+	// If the player's own entry has already gone, whoever took it is starting a game with them, so the opponent
+	// goes back in the queue to wait for someone else
+	if _, err := h.MatchQueue.Remove(playerId); err != nil {
+		if !errors.Is(err, errs.ErrNotFound) {
+			return db.QueueEntry{}, false, err
+		}
+		if _, err := h.MatchQueue.Enqueue(opponent.Id, gameType); err != nil {
+			return db.QueueEntry{}, false, err
+		}
+		return db.QueueEntry{}, false, nil
+	}
+
+	return opponent, true, nil
+}
+
+// findWaitingOpponent takes opponents queued before queuedBefore off the queue until it finds one that is still
+// connected
+func (h *Handlers) findWaitingOpponent(playerId string, gameType string, queuedBefore int64) (db.QueueEntry, error) {
+	for {
+		opponent, err := h.MatchQueue.Dequeue(gameType, playerId, queuedBefore)
+		if err != nil {
+			return db.QueueEntry{}, err
+		}
+
+		p, err := h.Players.GetPlayer(opponent.Id)
+		if err == nil && p.ConnectionId != "" {
+			return opponent, nil
+		} else if err != nil && !errors.Is(err, errs.ErrNotFound) {
+			return db.QueueEntry{}, err
+		}
+
+		log.Infof("Player with ID %s left the match queue without cancelling", opponent.Id)
+	}
+}
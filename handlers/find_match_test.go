@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// racingMatchQueue stands in for other players searching at the same moment as the caller. The caller's first look
+// finds the queue empty, as if the opponent queued just after it, and afterDequeue runs once the caller takes an
+// opponent off the queue
+type racingMatchQueue struct {
+	*db.InMemoryMatchQueueRepository
+	dequeues     int
+	afterDequeue func()
+}
+
+func (q *racingMatchQueue) Dequeue(boardSize string, excludeId string, queuedBefore int64) (db.QueueEntry, error) {
+	q.dequeues++
+	if q.dequeues == 1 {
+		return q.InMemoryMatchQueueRepository.Dequeue("", excludeId, queuedBefore)
+	}
+
+	entry, err := q.InMemoryMatchQueueRepository.Dequeue(boardSize, excludeId, queuedBefore)
+	if err == nil && q.afterDequeue != nil {
+		q.afterDequeue()
+	}
+	return entry, err
+}
+
+func TestHandlers_FindMatch(t *testing.T) {
+	tests := []struct {
+		name               string
+		waiting            map[string]string
+		body               string
+		expectedStatusCode int
+		expectedPlayerX    string
+		expectedRecipients []string
+		expectedWaiting    []string
+	}{
+		{
+			name:               "nobody waiting",
+			body:               `{}`,
+			expectedStatusCode: http.StatusOK,
+			expectedRecipients: []string{},
+			expectedWaiting:    []string{"playerO"},
+		},
+		{
+			name:               "opponent waiting",
+			waiting:            map[string]string{"playerX": "classic 3x3x3"},
+			body:               `{}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerX:    "playerX",
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "opponent waiting for a different board size",
+			waiting:            map[string]string{"playerX": "classic 15x15x5"},
+			body:               `{}`,
+			expectedStatusCode: http.StatusOK,
+			expectedRecipients: []string{},
+			expectedWaiting:    []string{"playerX", "playerO"},
+		},
+		{
+			name:               "opponent waiting for the same custom board size",
+			waiting:            map[string]string{"playerX": "classic 15x15x5"},
+			body:               `{"rows": 15, "columns": 15, "winLength": 5}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerX:    "playerX",
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "opponent waiting for the same variant",
+			waiting:            map[string]string{"playerX": "misere 3x3x3"},
+			body:               `{"variant": "misere"}`,
+			expectedStatusCode: http.StatusOK,
+			expectedPlayerX:    "playerX",
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "waiting opponent has disconnected",
+			waiting:            map[string]string{"disconnectedPlayer": "classic 3x3x3"},
+			body:               `{}`,
+			expectedStatusCode: http.StatusOK,
+			expectedRecipients: []string{},
+			expectedWaiting:    []string{"playerO"},
+		},
+		{
+			name:               "waiting opponent is offline",
+			waiting:            map[string]string{"someOtherPlayer": "classic 3x3x3"},
+			body:               `{}`,
+			expectedStatusCode: http.StatusOK,
+			expectedRecipients: []string{},
+			expectedWaiting:    []string{"playerO"},
+		},
+		{
+			name:               "invalid board size",
+			body:               `{"rows": 100}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "unknown variant",
+			body:               `{"variant": "cubic"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+			_, _ = h.Players.DisconnectPlayer("someOtherPlayer", "someOtherPlayer")
+			for id, boardSize := range test.waiting {
+				_, _ = h.MatchQueue.Enqueue(id, boardSize)
+			}
+
+			response, err := router.Decode(h.FindMatch)(context.Background(), newWebsocketEvent("playerO", test.body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, test.expectedRecipients, messenger.recipients())
+
+			if test.expectedPlayerX != "" {
+				g := unmarshalGame(t, response)
+				assert.Equal(t, test.expectedPlayerX, g.PlayerX)
+				assert.Equal(t, "playerO", g.PlayerO)
+
+				_, err := h.Games.GetGame(g.Id)
+				assert.Equal(t, nil, err)
+			}
+
+			for _, id := range test.expectedWaiting {
+				_, err := h.MatchQueue.Remove(id)
+				assert.Equal(t, nil, err, id)
+			}
+			_, err = h.MatchQueue.Dequeue("classic 3x3x3", "", time.Now().UnixNano())
+			assert.IsType(t, &db.EntityDoesNotExistError{}, err)
+		})
+	}
+}
+
+func TestHandlers_FindMatch_DifferentVariantsAreNotPaired(t *testing.T) {
+	h, messenger := newTestHandlers()
+
+	_, _ = router.Decode(h.FindMatch)(context.Background(), newWebsocketEvent("playerX", `{"variant": "misere"}`))
+	response, _ := router.Decode(h.FindMatch)(context.Background(), newWebsocketEvent("playerO", `{"variant": "classic"}`))
+
+	assert.Contains(t, response.Body, "Waiting for an opponent to play classic 3x3x3")
+	assert.Equal(t, []string{}, messenger.recipients())
+	_, err := h.MatchQueue.Remove("playerX")
+	assert.Equal(t, nil, err)
+	_, err = h.MatchQueue.Remove("playerO")
+	assert.Equal(t, nil, err)
+}
+
+func TestHandlers_FindMatch_PairsTwoPlayers(t *testing.T) {
+	h, messenger := newTestHandlers()
+
+	_, _ = router.Decode(h.FindMatch)(context.Background(), newWebsocketEvent("playerX", `{}`))
+	response, _ := router.Decode(h.FindMatch)(context.Background(), newWebsocketEvent("playerO", `{}`))
+
+	g := unmarshalGame(t, response)
+	assert.Equal(t, "playerX", g.PlayerX)
+	assert.Equal(t, "playerO", g.PlayerO)
+	assert.Equal(t, []string{"playerX"}, messenger.recipients())
+}
+
+func TestHandlers_FindMatch_SearchingAtTheSameMoment(t *testing.T) {
+	h, messenger := newTestHandlers()
+	queue := &racingMatchQueue{InMemoryMatchQueueRepository: db.NewInMemoryMatchQueueRepository()}
+	h.MatchQueue = queue
+	_, _ = queue.Enqueue("playerX", "classic 3x3x3")
+
+	response, _ := router.Decode(h.FindMatch)(context.Background(), newWebsocketEvent("playerO", `{}`))
+
+	g := unmarshalGame(t, response)
+	assert.Equal(t, "playerX", g.PlayerX)
+	assert.Equal(t, "playerO", g.PlayerO)
+	assert.Equal(t, []string{"playerX"}, messenger.recipients())
+	_, err := queue.Remove("playerO")
+	assert.IsType(t, &db.EntityDoesNotExistError{}, err)
+}
+
+func TestHandlers_FindMatch_ClaimedWhileClaimingOpponent(t *testing.T) {
+	h, messenger := newTestHandlers()
+	queue := &racingMatchQueue{InMemoryMatchQueueRepository: db.NewInMemoryMatchQueueRepository()}
+	queue.afterDequeue = func() {
+		// Another player takes the caller off the queue, and will start a game with them
+		_, _ = queue.InMemoryMatchQueueRepository.Remove("playerO")
+	}
+	h.MatchQueue = queue
+	_, _ = queue.Enqueue("playerX", "classic 3x3x3")
+
+	response, err := router.Decode(h.FindMatch)(context.Background(), newWebsocketEvent("playerO", `{}`))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Contains(t, response.Body, "Waiting for an opponent")
+	assert.Equal(t, []string{}, messenger.recipients())
+	_, err = queue.Remove("playerX")
+	assert.Equal(t, nil, err)
+}
+
+func TestHandlers_CancelMatch(t *testing.T) {
+	h, _ := newTestHandlers()
+	_, _ = h.MatchQueue.Enqueue("playerX", "classic 3x3x3")
+
+	response, err := h.CancelMatch(context.Background(), newWebsocketEvent("playerX", `{}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, err = h.CancelMatch(context.Background(), newWebsocketEvent("playerX", `{}`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type GameActionRequest struct {
+	Id string `json:"id"`
+}
+
+func (r *GameActionRequest) Validate() error {
+	return router.Required("id", r.Id)
+}
+
+// applyGameAction loads the game named in the request, applies the action on behalf of the caller, saves it, and
+// pushes the result to the opponent and any spectators. It backs the routes that change a game other than by a move
+func (h *Handlers) applyGameAction(websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody GameActionRequest, action func(g *game.Game, playerId string) error) (events.APIGatewayProxyResponse, error) {
+	gameId := requestBody.Id
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	g, err := h.Games.GetGame(gameId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	err = action(&g, playerId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	updatedGame, err := h.Games.UpdateGame(g)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if updatedGame.IsFinished() {
+		log.Infof("Game %s has finished with status %s", gameId, updatedGame.Status)
+	}
+
+	otherPlayerId := g.GetOtherPlayer(playerId)
+	if err = h.sendToPlayer(websocketEvent, otherPlayerId, utils.GameUpdated, updatedGame); err != nil {
+		log.Errorf("An error occurred when sending message from %s to %s - %s", playerId, otherPlayerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+	h.sendToSpectators(websocketEvent, gameId, updatedGame)
+
+	return utils.OkResponse(updatedGame), nil
+}
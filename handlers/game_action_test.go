@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_GameActions(t *testing.T) {
+	tests := []struct {
+		name               string
+		handler            func(*Handlers, context.Context, events.APIGatewayWebsocketProxyRequest, GameActionRequest) (events.APIGatewayProxyResponse, error)
+		drawOfferedBy      game.Piece
+		connectionId       string
+		gameId             string
+		expectedStatusCode int
+		expectedStatus     game.Status
+		expectedDrawOffer  game.Piece
+		expectedRecipients []string
+	}{
+		{
+			name:               "resign",
+			handler:            (*Handlers).Resign,
+			connectionId:       "playerX",
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.Resigned,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "resign when not a player",
+			handler:            (*Handlers).Resign,
+			connectionId:       "someOtherPlayer",
+			expectedStatusCode: http.StatusForbidden,
+			expectedStatus:     game.InProgress,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "resign from a game that does not exist",
+			handler:            (*Handlers).Resign,
+			connectionId:       "playerX",
+			gameId:             "does-not-exist",
+			expectedStatusCode: http.StatusNotFound,
+			expectedStatus:     game.InProgress,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "offer draw",
+			handler:            (*Handlers).OfferDraw,
+			connectionId:       "playerO",
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.InProgress,
+			expectedDrawOffer:  game.O,
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "offer draw twice",
+			handler:            (*Handlers).OfferDraw,
+			drawOfferedBy:      game.O,
+			connectionId:       "playerO",
+			expectedStatusCode: http.StatusConflict,
+			expectedStatus:     game.InProgress,
+			expectedDrawOffer:  game.O,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "accept draw",
+			handler:            (*Handlers).AcceptDraw,
+			drawOfferedBy:      game.X,
+			connectionId:       "playerO",
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.DrawAgreed,
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "accept draw that was not offered",
+			handler:            (*Handlers).AcceptDraw,
+			connectionId:       "playerO",
+			expectedStatusCode: http.StatusConflict,
+			expectedStatus:     game.InProgress,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "decline draw",
+			handler:            (*Handlers).DeclineDraw,
+			drawOfferedBy:      game.X,
+			connectionId:       "playerO",
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.InProgress,
+			expectedRecipients: []string{"playerX"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+			newGame := game.NewGame("playerX", "playerO")
+			newGame.DrawOfferedBy = test.drawOfferedBy
+			g, _ := h.Games.CreateGame(*newGame)
+
+			gameId := g.Id
+			if test.gameId != "" {
+				gameId = test.gameId
+			}
+
+			body := fmt.Sprintf(`{"id": "%s"}`, gameId)
+			response, err := router.Decode(func(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody GameActionRequest) (events.APIGatewayProxyResponse, error) {
+				return test.handler(h, ctx, websocketEvent, requestBody)
+			})(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, test.expectedRecipients, messenger.recipients())
+
+			storedGame, _ := h.Games.GetGame(g.Id)
+			assert.Equal(t, test.expectedStatus, storedGame.Status)
+			assert.Equal(t, test.expectedDrawOffer, storedGame.DrawOfferedBy)
+		})
+	}
+}
+
+func TestHandlers_OfferDraw_ComputerDeclines(t *testing.T) {
+	h, _ := newTestHandlers()
+	g, _ := h.Games.CreateGame(*game.NewGame("playerX", engine.PlayerId))
+
+	body := fmt.Sprintf(`{"id": "%s"}`, g.Id)
+	response, _ := router.Decode(h.OfferDraw)(context.Background(), newWebsocketEvent("playerX", body))
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	updatedGame := unmarshalGame(t, response)
+	assert.Equal(t, game.InProgress, updatedGame.Status)
+	assert.Equal(t, game.Piece(""), updatedGame.DrawOfferedBy)
+}
+
+func TestHandlers_Resign_NotifiesSpectators(t *testing.T) {
+	h, messenger := newTestHandlers()
+	g, _ := h.Games.CreateGame(*game.NewGame("playerX", "playerO"))
+	_, _ = h.Spectators.AddSpectator(g.Id, "someOtherPlayer")
+
+	body := fmt.Sprintf(`{"id": "%s"}`, g.Id)
+	_, _ = router.Decode(h.Resign)(context.Background(), newWebsocketEvent("playerO", body))
+
+	assert.Equal(t, []string{"playerX", "someOtherPlayer"}, messenger.recipients())
+}
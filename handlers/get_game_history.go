@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type GetGameHistoryRequest struct {
+	Id  string `json:"id"`
+	Ply *int   `json:"ply"`
+}
+
+func (r *GetGameHistoryRequest) Validate() error {
+	return router.Required("id", r.Id)
+}
+
+type GetGameHistoryResponse struct {
+	Id          string
+	Moves       []game.Move
+	Ply         int
+	Board       [][]game.Piece
+	CurrentTurn game.Piece
+}
+
+func (h *Handlers) GetGameHistory(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody GetGameHistoryRequest) (events.APIGatewayProxyResponse, error) {
+	gameId := requestBody.Id
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	g, err := h.Games.GetGame(gameId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if !g.CanView(playerId) {
+		log.Infof("Player with ID %s tried to retrieve history of game %s which is private and does not belong to them", playerId, gameId)
+		return utils.ForbiddenResponse(), nil
+	}
+
+	ply := len(g.Moves)
+	if requestBody.Ply != nil {
+		ply = *requestBody.Ply
+	}
+
+	replay, err := g.ReplayTo(ply)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	return utils.OkResponse(GetGameHistoryResponse{
+		Id:          g.Id,
+		Moves:       g.Moves,
+		Ply:         ply,
+		Board:       replay.Board,
+		CurrentTurn: replay.CurrentTurn,
+	}), nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_GetGame(t *testing.T) {
+	tests := []struct {
+		name               string
+		connectionId       string
+		gameId             string
+		isPublic           bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "player X",
+			connectionId:       "playerX",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "player O",
+			connectionId:       "playerO",
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "not a player",
+			connectionId:       "someOtherPlayer",
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "spectator of public game",
+			connectionId:       "someOtherPlayer",
+			isPublic:           true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "game does not exist",
+			connectionId:       "playerX",
+			gameId:             "does-not-exist",
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, _ := newTestHandlers()
+			newGame := game.NewGame("playerX", "playerO")
+			newGame.IsPublic = test.isPublic
+			g, _ := h.Games.CreateGame(*newGame)
+
+			gameId := g.Id
+			if test.gameId != "" {
+				gameId = test.gameId
+			}
+
+			body := fmt.Sprintf(`{"id": "%s"}`, gameId)
+			response, err := router.Decode(h.GetGame)(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			if test.expectedStatusCode == http.StatusOK {
+				assert.Equal(t, g, unmarshalGame(t, response))
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type Handlers struct {
+	Games       db.GameRepository
+	Connections db.ConnectionRepository
+	Players     db.PlayerRepository
+	MatchQueue  db.MatchQueueRepository
+	Spectators  db.SpectatorRepository
+	Invitations db.InvitationRepository
+	Messenger   utils.Messenger
+}
+
+func NewDynamoHandlers() *Handlers {
+	client := db.NewClient()
+	return &Handlers{
+		Games:       db.NewDynamoGameRepository(client),
+		Connections: db.NewDynamoConnectionRepository(client),
+		Players:     db.NewDynamoPlayerRepository(client),
+		MatchQueue:  db.NewDynamoMatchQueueRepository(client),
+		Spectators:  db.NewDynamoSpectatorRepository(client),
+		Invitations: db.NewDynamoInvitationRepository(client),
+		Messenger:   &utils.ApiGatewayMessenger{},
+	}
+}
+
+// getPlayerId is the principal the $connect authorizer verified for the connection. Connections opened before the
+// authorizer was deployed have no principal, so are looked up instead
+func (h *Handlers) getPlayerId(websocketEvent events.APIGatewayWebsocketProxyRequest) (string, error) {
+	if principalId := auth.PrincipalId(websocketEvent); principalId != "" {
+		return principalId, nil
+	}
+
+	c, err := h.Connections.GetConnection(websocketEvent.RequestContext.ConnectionID)
+	if err != nil {
+		return "", err
+	}
+
+	// Connections made before players existed were their own player
+	if c.PlayerId == "" {
+		return c.Id, nil
+	}
+	return c.PlayerId, nil
+}
+
+// getDisplayName is the name from the player's token, or their ID if the token had no name
+func getDisplayName(websocketEvent events.APIGatewayWebsocketProxyRequest, playerId string) string {
+	if displayName := auth.DisplayName(websocketEvent); displayName != "" {
+		return displayName
+	}
+	return playerId
+}
+
+// sendToPlayer pushes a message to the player's current connection. Players who are offline will pick up the
+// latest state when they reconnect, so they are skipped rather than treated as an error
+func (h *Handlers) sendToPlayer(websocketEvent events.APIGatewayWebsocketProxyRequest, playerId string, messageType utils.MessageType, payload interface{}) error {
+	if playerId == engine.PlayerId {
+		return nil
+	}
+
+	p, err := h.Players.GetPlayer(playerId)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			log.Infof("Player with ID %s has never connected, so was not sent a message", playerId)
+			return nil
+		}
+		return err
+	}
+
+	if p.ConnectionId == "" {
+		log.Infof("Player with ID %s is offline, so was not sent a message", playerId)
+		return nil
+	}
+
+	return h.Messenger.SendMessage(websocketEvent, p.ConnectionId, utils.NewEnvelope(messageType, payload))
+}
+
+// sendToSpectators pushes a message to every connection watching the game. A spectator who can't be reached shouldn't
+// stop the others from being updated, so failures are only logged
+func (h *Handlers) sendToSpectators(websocketEvent events.APIGatewayWebsocketProxyRequest, gameId string, g game.Game) {
+	spectators, err := h.Spectators.ListSpectators(gameId)
+	if err != nil {
+		log.Errorf("An error occurred while retrieving spectators of game %s - %s", gameId, err)
+		return
+	}
+
+	for _, s := range spectators {
+		if err := h.Messenger.SendMessage(websocketEvent, s.ConnectionId, utils.NewEnvelope(utils.GameUpdated, g)); err != nil {
+			log.Errorf("An error occurred when sending game %s to spectator %s - %s", gameId, s.ConnectionId, err)
+		}
+	}
+}
+
+func unknownConnectionResponse(err error) events.APIGatewayProxyResponse {
+	if errors.Is(err, errs.ErrNotFound) {
+		log.Info(err)
+		return utils.ForbiddenResponse()
+	}
+	return utils.ErrorResponse(err)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	"sync"
+	"testing"
+)
+
+type sentMessage struct {
+	to      string
+	message interface{}
+}
+
+type recordingMessenger struct {
+	mutex    sync.Mutex
+	messages []sentMessage
+}
+
+func (m *recordingMessenger) SendMessage(_ events.APIGatewayWebsocketProxyRequest, messageTo string, message interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.messages = append(m.messages, sentMessage{to: messageTo, message: message})
+	return nil
+}
+
+// recipients are who was sent each message other than pushes about the lobby, which are sent on almost every
+// connection so are left to lobbyRecipients
+func (m *recordingMessenger) recipients() []string {
+	return m.recipientsOf(func(messageType utils.MessageType) bool { return !isLobbyPush(messageType) })
+}
+
+func (m *recordingMessenger) lobbyRecipients() []string {
+	return m.recipientsOf(isLobbyPush)
+}
+
+func isLobbyPush(messageType utils.MessageType) bool {
+	return messageType == utils.LobbyUpdated || messageType == utils.PlayerJoined || messageType == utils.PlayerLeft
+}
+
+func (m *recordingMessenger) recipientsOf(include func(messageType utils.MessageType) bool) []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	recipients := []string{}
+	for _, message := range m.messages {
+		envelope, _ := message.message.(utils.Envelope)
+		if include(envelope.Type) {
+			recipients = append(recipients, message.to)
+		}
+	}
+	return recipients
+}
+
+// newTestHandlers connects playerX, playerO and someOtherPlayer, each on a connection with the same ID as the player
+func newTestHandlers() (*Handlers, *recordingMessenger) {
+	messenger := &recordingMessenger{}
+	h := &Handlers{
+		Games:       db.NewInMemoryGameRepository(),
+		Connections: db.NewInMemoryConnectionRepository(),
+		Players:     db.NewInMemoryPlayerRepository(),
+		MatchQueue:  db.NewInMemoryMatchQueueRepository(),
+		Spectators:  db.NewInMemorySpectatorRepository(),
+		Invitations: db.NewInMemoryInvitationRepository(),
+		Messenger:   messenger,
+	}
+
+	for _, playerId := range []string{"playerX", "playerO", "someOtherPlayer"} {
+		connect(h, playerId, playerId)
+	}
+
+	return h, messenger
+}
+
+func connect(h *Handlers, connectionId string, playerId string) {
+	_, _ = h.Connections.CreateConnection(connectionId, playerId)
+	_, _ = h.Players.ConnectPlayer(playerId, connectionId, playerId)
+}
+
+func newWebsocketEvent(connectionId string, body string) events.APIGatewayWebsocketProxyRequest {
+	return events.APIGatewayWebsocketProxyRequest{
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			ConnectionID: connectionId,
+			DomainName:   "localhost",
+			Stage:        "test",
+		},
+		Body: body,
+	}
+}
+
+func unmarshalPayload(t *testing.T, response events.APIGatewayProxyResponse, payload interface{}) {
+	var envelope struct {
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(response.Body), &envelope); err != nil {
+		t.Fatalf("response body %s is not an envelope - %s", response.Body, err)
+	}
+	if err := json.Unmarshal(envelope.Payload, payload); err != nil {
+		t.Fatalf("response payload %s is not a %T - %s", envelope.Payload, payload, err)
+	}
+}
+
+func unmarshalGame(t *testing.T, response events.APIGatewayProxyResponse) game.Game {
+	var g game.Game
+	unmarshalPayload(t, response, &g)
+	return g
+}
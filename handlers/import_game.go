@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type ImportGameRequest struct {
+	Notation string `json:"notation"`
+	IsPublic bool   `json:"isPublic"`
+}
+
+func (r *ImportGameRequest) Validate() error {
+	return router.Required("notation", r.Notation)
+}
+
+// ImportGame stores a game read from its notation as a new game. Players can only import games they played in, and a
+// game that was still being played is imported as abandoned, so it can be replayed and analysed but not played on. The
+// opponent named in the notation is replaced by game.ImportedOpponentId
+func (h *Handlers) ImportGame(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody ImportGameRequest) (events.APIGatewayProxyResponse, error) {
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	importedGame, err := game.ParseNotation(requestBody.Notation)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if !importedGame.IsPlayer(playerId) {
+		log.Infof("Player with ID %s tried to import a game between %s and %s", playerId, importedGame.PlayerX, importedGame.PlayerO)
+		return utils.ForbiddenResponse(), nil
+	}
+
+	anonymiseOpponent(importedGame, playerId)
+	if !importedGame.IsFinished() {
+		importedGame.Abandon()
+	}
+	importedGame.IsPublic = requestBody.IsPublic
+
+	g, err := h.Games.CreateGame(*importedGame)
+	if err != nil {
+		log.Errorf("An error occurred when creating game - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(g), nil
+}
+
+func anonymiseOpponent(g *game.Game, playerId string) {
+	if g.PlayerX != playerId {
+		g.PlayerX = game.ImportedOpponentId
+	}
+	if g.PlayerO != playerId {
+		g.PlayerO = game.ImportedOpponentId
+	}
+	for i := range g.Moves {
+		if g.Moves[i].Player != playerId {
+			g.Moves[i].Player = game.ImportedOpponentId
+		}
+	}
+}
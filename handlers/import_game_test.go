@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_ImportGame(t *testing.T) {
+	tests := []struct {
+		name               string
+		connectionId       string
+		moves              []int
+		notation           string
+		expectedStatusCode int
+		expectedStatus     game.Status
+	}{
+		{
+			name:               "finished game",
+			connectionId:       "playerX",
+			moves:              []int{0, 3, 1, 4, 2},
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.XWon,
+		},
+		{
+			name:               "game in progress",
+			connectionId:       "playerO",
+			moves:              []int{4, 0},
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.Abandoned,
+		},
+		{
+			name:               "not a player",
+			connectionId:       "someOtherPlayer",
+			moves:              []int{0, 3, 1, 4, 2},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "invalid notation",
+			connectionId:       "playerX",
+			notation:           "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n1. 4 4 *",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+
+			notation := test.notation
+			if notation == "" {
+				original := game.NewGame("playerX", "playerO")
+				for i, move := range test.moves {
+					player := original.PlayerX
+					if i%2 == 1 {
+						player = original.PlayerO
+					}
+					_ = original.MakeMove(player, move)
+				}
+				notation = original.Notation()
+			}
+
+			body, _ := json.Marshal(ImportGameRequest{Notation: notation, IsPublic: true})
+			response, err := router.Decode(h.ImportGame)(context.Background(), newWebsocketEvent(test.connectionId, string(body)))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, []string{}, messenger.recipients())
+			if test.expectedStatusCode == http.StatusOK {
+				g := unmarshalGame(t, response)
+				assert.Equal(t, test.expectedStatus, g.Status)
+				assert.Equal(t, len(test.moves), len(g.Moves))
+				assert.True(t, g.IsPublic)
+
+				storedGame, err := h.Games.GetGame(g.Id)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, g.Moves, storedGame.Moves)
+			}
+		})
+	}
+}
+
+func TestHandlers_ImportGame_OpponentIsAnonymous(t *testing.T) {
+	h, _ := newTestHandlers()
+	original := game.NewGame("playerX", "playerO")
+	_ = original.MakeMove("playerX", 4)
+	_ = original.MakeMove("playerO", 0)
+
+	body, _ := json.Marshal(ImportGameRequest{Notation: original.Notation()})
+	response, _ := router.Decode(h.ImportGame)(context.Background(), newWebsocketEvent("playerX", string(body)))
+
+	g := unmarshalGame(t, response)
+	assert.Equal(t, "playerX", g.PlayerX)
+	assert.Equal(t, game.ImportedOpponentId, g.PlayerO)
+	assert.Equal(t, "playerX", g.Moves[0].Player)
+	assert.Equal(t, game.ImportedOpponentId, g.Moves[1].Player)
+	games, err := h.Games.ListGamesForPlayer("playerO")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(games))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type OwnInvitationError struct {
+	code string
+}
+
+func (e *OwnInvitationError) Error() string {
+	return fmt.Sprintf("invitation %s was created by you, so must be joined by another player", e.code)
+}
+
+func (e *OwnInvitationError) Code() string {
+	return "OWN_INVITATION"
+}
+
+func (e *OwnInvitationError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+type JoinGameRequest struct {
+	Code string `json:"code"`
+}
+
+func (r *JoinGameRequest) Validate() error {
+	return router.Required("code", r.Code)
+}
+
+// JoinGame accepts an invitation with its join code, starting the game with the invitation's creator playing X. Each
+// code can only be used once, so a second player trying the same code is told it doesn't exist
+func (h *Handlers) JoinGame(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody JoinGameRequest) (events.APIGatewayProxyResponse, error) {
+	code := requestBody.Code
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	// Checked before redeeming the invitation, so that creators trying their own code don't use it up
+	invitation, err := h.Invitations.GetInvitation(code)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+	if invitation.PlayerId == playerId {
+		return utils.ErrorResponse(&OwnInvitationError{code: invitation.Id}), nil
+	}
+
+	invitation, err = h.Invitations.RedeemInvitation(code)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	newGame, err := invitation.Settings.NewGame(invitation.PlayerId, playerId)
+	if err != nil {
+		log.Errorf("An error occurred while creating the game for invitation %s - %s", invitation.Id, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	g, err := h.Games.CreateGame(*newGame)
+	if err != nil {
+		log.Errorf("An error occurred when creating game - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	if err = h.sendToPlayer(websocketEvent, invitation.PlayerId, utils.GameStarted, g); err != nil {
+		log.Errorf("An error occurred when sending message from %s to %s - %s", playerId, invitation.PlayerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	if invitation.Settings.IsPublic {
+		h.sendLobbyUpdate(websocketEvent)
+	}
+
+	return utils.OkResponse(g), nil
+}
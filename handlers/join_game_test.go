@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHandlers_JoinGame(t *testing.T) {
+	tests := []struct {
+		name               string
+		connectionId       string
+		code               func(code string) string
+		expectedStatusCode int
+		expectedRecipients []string
+	}{
+		{
+			name:               "by another player",
+			connectionId:       "playerO",
+			code:               func(code string) string { return code },
+			expectedStatusCode: http.StatusOK,
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "with a code typed in lower case",
+			connectionId:       "playerO",
+			code:               func(code string) string { return strings.ToLower(code[:3]) + "-" + code[3:] },
+			expectedStatusCode: http.StatusOK,
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "by its creator",
+			connectionId:       "playerX",
+			code:               func(code string) string { return code },
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "unknown code",
+			connectionId:       "playerO",
+			code:               func(string) string { return "ZZZZZZZ" },
+			expectedStatusCode: http.StatusNotFound,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "no code",
+			connectionId:       "playerO",
+			code:               func(string) string { return "" },
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+			settings := game.Settings{Rows: 4, Columns: 4, WinLength: 3, IsPublic: true}
+			invitation, _ := h.Invitations.CreateInvitation("playerX", "Player X", settings)
+
+			body := fmt.Sprintf(`{"code": "%s"}`, test.code(invitation.Id))
+			response, err := router.Decode(h.JoinGame)(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, test.expectedRecipients, messenger.recipients())
+			if test.expectedStatusCode == http.StatusOK {
+				g := unmarshalGame(t, response)
+				assert.Equal(t, "playerX", g.PlayerX)
+				assert.Equal(t, "playerO", g.PlayerO)
+				assert.Equal(t, 4, g.Rows())
+				assert.True(t, g.IsPublic)
+
+				storedGame, err := h.Games.GetGame(g.Id)
+				assert.Equal(t, nil, err)
+				assert.Equal(t, g.Id, storedGame.Id)
+
+				_, err = h.Invitations.GetInvitation(invitation.Id)
+				assert.NotEqual(t, nil, err)
+			} else if test.connectionId == "playerX" {
+				_, err = h.Invitations.GetInvitation(invitation.Id)
+				assert.Equal(t, nil, err)
+			}
+		})
+	}
+}
+
+func TestHandlers_JoinGame_SingleUse(t *testing.T) {
+	h, messenger := newTestHandlers()
+	invitation, _ := h.Invitations.CreateInvitation("playerX", "Player X", game.Settings{})
+	body := fmt.Sprintf(`{"code": "%s"}`, invitation.Id)
+
+	response, _ := router.Decode(h.JoinGame)(context.Background(), newWebsocketEvent("playerO", body))
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, _ = router.Decode(h.JoinGame)(context.Background(), newWebsocketEvent("someOtherPlayer", body))
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, []string{"playerX"}, messenger.recipients())
+}
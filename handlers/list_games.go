@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+// ListGames returns the caller's unfinished games, so they can pick up where they left off after reconnecting
+func (h *Handlers) ListGames(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	games, err := h.Games.ListGamesForPlayer(playerId)
+	if err != nil {
+		log.Errorf("An error occurred while listing games for player with ID %s - %s", playerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	inProgressGames := []game.Game{}
+	for _, g := range games {
+		if !g.IsFinished() {
+			inProgressGames = append(inProgressGames, g)
+		}
+	}
+
+	return utils.OkResponse(inProgressGames), nil
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_ListGames(t *testing.T) {
+	h, _ := newTestHandlers()
+	asX, _ := h.Games.CreateGame(*game.NewGame("playerX", "playerO"))
+	asO, _ := h.Games.CreateGame(*game.NewGame("someOtherPlayer", "playerX"))
+	_, _ = h.Games.CreateGame(*game.NewGame("someOtherPlayer", "playerO"))
+	finished := game.NewGame("playerX", "playerO")
+	finished.Status = game.Draw
+	_, _ = h.Games.CreateGame(*finished)
+
+	response, err := h.ListGames(context.Background(), newWebsocketEvent("playerX", ""))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var games []game.Game
+	unmarshalPayload(t, response, &games)
+	var ids []string
+	for _, g := range games {
+		ids = append(ids, g.Id)
+	}
+	assert.ElementsMatch(t, []string{asX.Id, asO.Id}, ids)
+}
+
+func TestHandlers_ListGames_UnknownConnection(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	response, err := h.ListGames(context.Background(), newWebsocketEvent("unknownConnection", ""))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
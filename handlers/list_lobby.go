@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+// Lobby is who can be played right now: the invitations to public games that are still open, and the players who are
+// online, who can be challenged with create-game
+type Lobby struct {
+	Invitations []LobbyInvitation
+	Players     []LobbyPlayer
+}
+
+type LobbyInvitation struct {
+	Code        string
+	PlayerId    string
+	DisplayName string
+	Settings    game.Settings
+	// ExpiresAt is when the code stops working, as a Unix timestamp in seconds
+	ExpiresAt int64
+}
+
+type LobbyPlayer struct {
+	Id          string
+	DisplayName string
+}
+
+// ListLobby returns the lobby. Players are told when it changes with lobby-updated, player-joined and player-left
+// pushes, so only need to call this once they connect
+func (h *Handlers) ListLobby(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if _, err := h.getPlayerId(websocketEvent); err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	lobby, _, err := h.getLobby()
+	if err != nil {
+		log.Errorf("An error occurred while listing the lobby - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(lobby), nil
+}
+
+// getLobby also returns the online players it was built from, as their connections aren't part of the lobby
+func (h *Handlers) getLobby() (Lobby, []db.Player, error) {
+	invitations, err := h.Invitations.ListPublicInvitations()
+	if err != nil {
+		return Lobby{}, nil, err
+	}
+
+	players, err := h.Players.ListOnlinePlayers()
+	if err != nil {
+		return Lobby{}, nil, err
+	}
+
+	lobby := Lobby{
+		Invitations: []LobbyInvitation{},
+		Players:     []LobbyPlayer{},
+	}
+	for _, invitation := range invitations {
+		lobby.Invitations = append(lobby.Invitations, LobbyInvitation{
+			Code:        invitation.Id,
+			PlayerId:    invitation.PlayerId,
+			DisplayName: invitation.DisplayName,
+			Settings:    invitation.Settings,
+			ExpiresAt:   invitation.Ttl,
+		})
+	}
+	for _, p := range players {
+		lobby.Players = append(lobby.Players, LobbyPlayer{Id: p.Id, DisplayName: p.DisplayName})
+	}
+
+	return lobby, players, nil
+}
+
+// sendLobbyUpdate pushes the lobby to every online player, other than the one whose request changed it
+func (h *Handlers) sendLobbyUpdate(websocketEvent events.APIGatewayWebsocketProxyRequest) {
+	lobby, players, err := h.getLobby()
+	if err != nil {
+		log.Errorf("An error occurred while listing the lobby - %s", err)
+		return
+	}
+
+	h.sendToOnlinePlayers(websocketEvent, players, utils.NewEnvelope(utils.LobbyUpdated, lobby))
+}
+
+// sendPresence tells every other online player that a player has come online or gone offline. Only the player who
+// changed is sent, rather than the whole lobby, as this happens on almost every connection
+func (h *Handlers) sendPresence(websocketEvent events.APIGatewayWebsocketProxyRequest, messageType utils.MessageType, player LobbyPlayer) {
+	players, err := h.Players.ListOnlinePlayers()
+	if err != nil {
+		log.Errorf("An error occurred while listing online players - %s", err)
+		return
+	}
+
+	h.sendToOnlinePlayers(websocketEvent, players, utils.NewEnvelope(messageType, player))
+}
+
+// sendToOnlinePlayers skips the connection whose request is being handled. Like sendToSpectators, a player who can't
+// be reached shouldn't stop the others from being sent the message, so failures are only logged
+func (h *Handlers) sendToOnlinePlayers(websocketEvent events.APIGatewayWebsocketProxyRequest, players []db.Player, envelope utils.Envelope) {
+	for _, p := range players {
+		if p.ConnectionId == "" || p.ConnectionId == websocketEvent.RequestContext.ConnectionID {
+			continue
+		}
+
+		if err := h.Messenger.SendMessage(websocketEvent, p.ConnectionId, envelope); err != nil {
+			log.Errorf("An error occurred when sending %s to player with ID %s - %s", envelope.Type, p.Id, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func unmarshalLobby(t *testing.T, h *Handlers, connectionId string) Lobby {
+	response, err := h.ListLobby(context.Background(), newWebsocketEvent(connectionId, ""))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	var lobby Lobby
+	unmarshalPayload(t, response, &lobby)
+	return lobby
+}
+
+func TestHandlers_ListLobby(t *testing.T) {
+	h, _ := newTestHandlers()
+	public, _ := h.Invitations.CreateInvitation("playerX", "Player X", game.Settings{IsPublic: true})
+	_, _ = h.Invitations.CreateInvitation("playerO", "Player O", game.Settings{})
+	_, _ = h.Disconnect(context.Background(), newWebsocketEvent("someOtherPlayer", ""))
+
+	lobby := unmarshalLobby(t, h, "playerO")
+
+	assert.Equal(t, []LobbyInvitation{
+		{
+			Code:        public.Id,
+			PlayerId:    "playerX",
+			DisplayName: "Player X",
+			Settings:    game.Settings{IsPublic: true},
+			ExpiresAt:   public.Ttl,
+		},
+	}, lobby.Invitations)
+	assert.ElementsMatch(t, []LobbyPlayer{
+		{Id: "playerX", DisplayName: "playerX"},
+		{Id: "playerO", DisplayName: "playerO"},
+	}, lobby.Players)
+}
+
+func TestHandlers_ListLobby_UnknownConnection(t *testing.T) {
+	h, _ := newTestHandlers()
+
+	response, _ := h.ListLobby(context.Background(), newWebsocketEvent("unknownConnection", ""))
+
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestHandlers_Connect_NamesPlayerFromToken(t *testing.T) {
+	h, _ := newTestHandlers()
+	websocketEvent := newWebsocketEvent("newConnection", "")
+	websocketEvent.RequestContext.Authorizer = auth.AuthorizerContext("newPlayer", map[string]interface{}{"displayName": "New Player"})
+
+	_, _ = h.Connect(context.Background(), websocketEvent)
+
+	assert.Contains(t, unmarshalLobby(t, h, "playerX").Players, LobbyPlayer{Id: "newPlayer", DisplayName: "New Player"})
+}
+
+func TestHandlers_LobbyUpdates(t *testing.T) {
+	tests := []struct {
+		name               string
+		change             func(h *Handlers) int
+		expectedType       utils.MessageType
+		expectedPayload    interface{}
+		expectedRecipients []string
+	}{
+		{
+			name: "player connects",
+			change: func(h *Handlers) int {
+				response, _ := h.Connect(context.Background(), newConnectEvent("newConnection", "newPlayer"))
+				return response.StatusCode
+			},
+			expectedType:       utils.PlayerJoined,
+			expectedPayload:    LobbyPlayer{Id: "newPlayer", DisplayName: "newPlayer"},
+			expectedRecipients: []string{"playerO", "playerX", "someOtherPlayer"},
+		},
+		{
+			name: "player reconnects while still connected",
+			change: func(h *Handlers) int {
+				response, _ := h.Connect(context.Background(), newConnectEvent("newConnection", "playerX"))
+				return response.StatusCode
+			},
+			expectedRecipients: []string{},
+		},
+		{
+			name: "player disconnects",
+			change: func(h *Handlers) int {
+				response, _ := h.Disconnect(context.Background(), newWebsocketEvent("playerX", ""))
+				return response.StatusCode
+			},
+			expectedType:       utils.PlayerLeft,
+			expectedPayload:    LobbyPlayer{Id: "playerX", DisplayName: "playerX"},
+			expectedRecipients: []string{"playerO", "someOtherPlayer"},
+		},
+		{
+			name: "public game is opened",
+			change: func(h *Handlers) int {
+				response, _ := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", `{"open": true, "isPublic": true}`))
+				return response.StatusCode
+			},
+			expectedType:       utils.LobbyUpdated,
+			expectedRecipients: []string{"playerO", "someOtherPlayer"},
+		},
+		{
+			name: "private game is opened",
+			change: func(h *Handlers) int {
+				response, _ := router.Decode(h.CreateGame)(context.Background(), newWebsocketEvent("playerX", `{"open": true}`))
+				return response.StatusCode
+			},
+			expectedRecipients: []string{},
+		},
+		{
+			name: "public game is joined",
+			change: func(h *Handlers) int {
+				invitation, _ := h.Invitations.CreateInvitation("playerX", "playerX", game.Settings{IsPublic: true})
+				body := fmt.Sprintf(`{"code": "%s"}`, invitation.Id)
+				response, _ := router.Decode(h.JoinGame)(context.Background(), newWebsocketEvent("playerO", body))
+				return response.StatusCode
+			},
+			expectedType:       utils.LobbyUpdated,
+			expectedRecipients: []string{"playerX", "someOtherPlayer"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+
+			statusCode := test.change(h)
+
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.ElementsMatch(t, test.expectedRecipients, messenger.lobbyRecipients())
+			for _, message := range messenger.messages {
+				if envelope := message.message.(utils.Envelope); isLobbyPush(envelope.Type) {
+					assert.Equal(t, test.expectedType, envelope.Type)
+					if test.expectedPayload != nil {
+						assert.Equal(t, test.expectedPayload, envelope.Payload)
+					}
+				}
+			}
+		})
+	}
+}
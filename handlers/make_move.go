@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type MakeMoveRequest struct {
+	Id   string `json:"id"`
+	Move int    `json:"move"`
+}
+
+func (r *MakeMoveRequest) Validate() error {
+	return router.Required("id", r.Id)
+}
+
+func (h *Handlers) MakeMove(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody MakeMoveRequest) (events.APIGatewayProxyResponse, error) {
+	gameId := requestBody.Id
+	move := requestBody.Move
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	g, err := h.Games.GetGame(gameId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if err = g.MakeMove(playerId, move); err != nil {
+		var outOfTimeErr *game.OutOfTimeError
+		if errors.As(err, &outOfTimeErr) {
+			if _, timeOutErr := h.timeOutGame(websocketEvent, g); timeOutErr != nil && !errors.Is(timeOutErr, errs.ErrRetryable) {
+				log.Errorf("An error occurred while timing out game with ID %s - %s", gameId, timeOutErr)
+				return utils.InternalServerErrorResponse(), nil
+			}
+		}
+		return utils.ErrorResponse(err), nil
+	}
+
+	computerMoved := g.CurrentPlayer() == engine.PlayerId && !g.IsFinished()
+	if computerMoved {
+		if err = makeComputerMove(&g); err != nil {
+			log.Errorf("An error occurred while making computer move in game %s - %s", gameId, err)
+			return utils.InternalServerErrorResponse(), nil
+		}
+	}
+
+	updatedGame, err := h.Games.UpdateGame(g)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if updatedGame.IsFinished() {
+		log.Infof("Game %s has finished with status %s", gameId, updatedGame.Status)
+	}
+
+	otherPlayerId := g.GetOtherPlayer(playerId)
+	if err = h.sendToPlayer(websocketEvent, otherPlayerId, utils.GameUpdated, updatedGame); err != nil {
+		log.Errorf("An error occurred when sending message from %s to %s - %s", playerId, otherPlayerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	// The computer's reply is pushed as well as returned, so clients can treat it like a move from any other opponent
+	if computerMoved {
+		if err = h.sendToPlayer(websocketEvent, playerId, utils.GameUpdated, updatedGame); err != nil {
+			log.Errorf("An error occurred when sending the computer's move to %s - %s", playerId, err)
+			return utils.InternalServerErrorResponse(), nil
+		}
+	}
+
+	h.sendToSpectators(websocketEvent, gameId, updatedGame)
+
+	return utils.OkResponse(updatedGame), nil
+
+}
+
+func makeComputerMove(g *game.Game) error {
+	difficulty, err := engine.ParseDifficulty(g.ComputerDifficulty)
+	if err != nil {
+		return err
+	}
+
+	move, err := engine.ChooseMove(g, difficulty)
+	if err != nil {
+		return err
+	}
+
+	return g.MakeMove(engine.PlayerId, move)
+}
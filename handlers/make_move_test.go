@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type conflictingGameRepository struct {
+	*db.InMemoryGameRepository
+}
+
+func (r *conflictingGameRepository) UpdateGame(g game.Game) (game.Game, error) {
+	// Simulate another request having moved first by bumping the stored version
+	storedGame, _ := r.InMemoryGameRepository.GetGame(g.Id)
+	_, _ = r.InMemoryGameRepository.UpdateGame(storedGame)
+	return r.InMemoryGameRepository.UpdateGame(g)
+}
+
+func TestHandlers_MakeMove(t *testing.T) {
+	tests := []struct {
+		name               string
+		variant            game.Variant
+		board              [][]game.Piece
+		playerO            string
+		connectionId       string
+		move               int
+		expectedStatusCode int
+		expectedStatus     game.Status
+		expectedRecipients []string
+	}{
+		{
+			name:               "valid move",
+			connectionId:       "playerX",
+			move:               4,
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.InProgress,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name: "winning move",
+			board: [][]game.Piece{
+				{game.X, game.X, game.EMPTY},
+				{game.O, game.O, game.EMPTY},
+				{game.EMPTY, game.EMPTY, game.EMPTY},
+			},
+			connectionId:       "playerX",
+			move:               2,
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.XWon,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:    "misere line loses",
+			variant: game.Misere,
+			board: [][]game.Piece{
+				{game.X, game.X, game.EMPTY},
+				{game.O, game.O, game.EMPTY},
+				{game.EMPTY, game.EMPTY, game.EMPTY},
+			},
+			connectionId:       "playerX",
+			move:               2,
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.OWon,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "wild move",
+			variant:            game.Wild,
+			connectionId:       "playerX",
+			move:               game.WildMove(4, game.O),
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.InProgress,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "numerical move with the wrong parity",
+			variant:            game.Numerical,
+			connectionId:       "playerX",
+			move:               game.NumericalMove(4, 2),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "against the computer",
+			playerO:            engine.PlayerId,
+			connectionId:       "playerX",
+			move:               0,
+			expectedStatusCode: http.StatusOK,
+			expectedStatus:     game.InProgress,
+			expectedRecipients: []string{"playerX"},
+		},
+		{
+			name:               "not a player",
+			connectionId:       "someOtherPlayer",
+			move:               4,
+			expectedStatusCode: http.StatusForbidden,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "not the player's turn",
+			connectionId:       "playerO",
+			move:               4,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "invalid move",
+			connectionId:       "playerX",
+			move:               9,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedRecipients: []string{},
+		},
+		{
+			name: "game has finished",
+			board: [][]game.Piece{
+				{game.X, game.X, game.X},
+				{game.O, game.O, game.EMPTY},
+				{game.EMPTY, game.EMPTY, game.EMPTY},
+			},
+			connectionId:       "playerX",
+			move:               5,
+			expectedStatusCode: http.StatusConflict,
+			expectedRecipients: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+
+			playerO := "playerO"
+			if test.playerO != "" {
+				playerO = test.playerO
+			}
+			newGame, _ := game.NewVariantGame(test.variant, "playerX", playerO, 0, 0, 0)
+			newGame.ComputerDifficulty = string(engine.Perfect)
+			if test.board != nil {
+				newGame.Board = test.board
+			}
+			g, _ := h.Games.CreateGame(*newGame)
+
+			body := fmt.Sprintf(`{"id": "%s", "move": %d}`, g.Id, test.move)
+			response, err := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, test.expectedRecipients, messenger.recipients())
+
+			storedGame, _ := h.Games.GetGame(g.Id)
+			if test.expectedStatusCode == http.StatusOK {
+				updatedGame := unmarshalGame(t, response)
+				assert.Equal(t, test.expectedStatus, updatedGame.Status)
+				assert.Equal(t, storedGame.Board, updatedGame.Board)
+				assert.Equal(t, storedGame.Version, updatedGame.Version)
+			} else {
+				assert.Equal(t, g.Board, storedGame.Board)
+			}
+		})
+	}
+}
+
+func TestHandlers_MakeMove_ComputerReplies(t *testing.T) {
+	h, messenger := newTestHandlers()
+	newGame := game.NewGame("playerX", engine.PlayerId)
+	newGame.ComputerDifficulty = string(engine.Perfect)
+	g, _ := h.Games.CreateGame(*newGame)
+
+	body := fmt.Sprintf(`{"id": "%s", "move": 0}`, g.Id)
+	response, _ := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerX", body))
+
+	updatedGame := unmarshalGame(t, response)
+	assert.Equal(t, 2, len(updatedGame.Moves))
+	assert.Equal(t, "playerX", updatedGame.Moves[0].Player)
+	assert.Equal(t, engine.PlayerId, updatedGame.Moves[1].Player)
+	assert.Equal(t, 4, updatedGame.Moves[1].Square)
+	assert.Equal(t, game.X, updatedGame.CurrentTurn)
+
+	assert.Equal(t, []string{"playerX"}, messenger.recipients())
+	push := messenger.messages[0].message.(utils.Envelope)
+	assert.Equal(t, utils.GameUpdated, push.Type)
+	assert.Equal(t, updatedGame.Moves, push.Payload.(game.Game).Moves)
+}
+
+func TestHandlers_MakeMove_GameDoesNotExist(t *testing.T) {
+	h, messenger := newTestHandlers()
+
+	response, err := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerX", `{"id": "does-not-exist", "move": 0}`))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	assert.Equal(t, []string{}, messenger.recipients())
+}
+
+func TestHandlers_MakeMove_ConcurrentModification(t *testing.T) {
+	h, messenger := newTestHandlers()
+	h.Games = &conflictingGameRepository{InMemoryGameRepository: db.NewInMemoryGameRepository()}
+	g, _ := h.Games.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	body := fmt.Sprintf(`{"id": "%s", "move": 0}`, g.Id)
+	response, err := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerX", body))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Contains(t, response.Body, `"retryable": true`)
+	assert.Equal(t, []string{}, messenger.recipients())
+}
+
+func TestHandlers_MakeMove_OutOfTime(t *testing.T) {
+	h, messenger := newTestHandlers()
+	g := createTimedGame(h, time.Now().Add(-time.Second))
+
+	body := fmt.Sprintf(`{"id": "%s", "move": 4}`, g.Id)
+	response, err := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerX", body))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Equal(t, []string{"playerX", "playerO"}, messenger.recipients())
+
+	storedGame, _ := h.Games.GetGame(g.Id)
+	assert.Equal(t, game.TimedOut, storedGame.Status)
+	assert.Equal(t, game.EMPTY, storedGame.Board[1][1])
+}
+
+func TestHandlers_MakeMove_ErrorCode(t *testing.T) {
+	h, _ := newTestHandlers()
+	g, _ := h.Games.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	body := fmt.Sprintf(`{"id": "%s", "move": 4}`, g.Id)
+	response, _ := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerO", body))
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.JSONEq(t, `{"version": 1, "error": {"code": "NOT_YOUR_TURN", "message": "it is not playerO's turn"}}`, response.Body)
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func (h *Handlers) OfferDraw(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody GameActionRequest) (events.APIGatewayProxyResponse, error) {
+	return h.applyGameAction(websocketEvent, requestBody, func(g *game.Game, playerId string) error {
+		if err := g.OfferDraw(playerId); err != nil {
+			return err
+		}
+
+		// The computer always plays on
+		if g.GetOtherPlayer(playerId) == engine.PlayerId && !g.IsFinished() {
+			return g.DeclineDraw(engine.PlayerId)
+		}
+		return nil
+	})
+}
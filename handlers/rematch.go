@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type RematchRequest struct {
+	Id string `json:"id"`
+}
+
+func (r *RematchRequest) Validate() error {
+	return router.Required("id", r.Id)
+}
+
+// Rematch starts a new game against the same opponent with the colours swapped, linked to the finished game so the
+// series can be followed in either direction
+func (h *Handlers) Rematch(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody RematchRequest) (events.APIGatewayProxyResponse, error) {
+	gameId := requestBody.Id
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	g, err := h.Games.GetGame(gameId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	rematch, err := g.Rematch(playerId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if rematch.CurrentPlayer() == engine.PlayerId {
+		if err = makeComputerMove(rematch); err != nil {
+			log.Errorf("An error occurred while making computer move in rematch of game %s - %s", gameId, err)
+			return utils.InternalServerErrorResponse(), nil
+		}
+	}
+
+	newGame, err := h.Games.CreateGame(*rematch)
+	if err != nil {
+		log.Errorf("An error occurred when creating game - %s", err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	g.NextGameId = newGame.Id
+	if _, err = h.Games.UpdateGame(g); err != nil {
+		// The opponent asked for a rematch at the same time, so theirs stands and this one is never played
+		newGame.Abandon()
+		if _, abandonErr := h.Games.UpdateGame(newGame); abandonErr != nil {
+			log.Errorf("An error occurred while abandoning game with ID %s - %s", newGame.Id, abandonErr)
+		}
+
+		return utils.ErrorResponse(err), nil
+	}
+
+	otherPlayerId := g.GetOtherPlayer(playerId)
+	if err = h.sendToPlayer(websocketEvent, otherPlayerId, utils.GameStarted, newGame); err != nil {
+		log.Errorf("An error occurred when sending message from %s to %s - %s", playerId, otherPlayerId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(newGame), nil
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/engine"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type racedRematchGameRepository struct {
+	*db.InMemoryGameRepository
+}
+
+func (r *racedRematchGameRepository) UpdateGame(g game.Game) (game.Game, error) {
+	// Simulate the opponent having linked their own rematch first
+	if g.NextGameId != "" {
+		storedGame, _ := r.InMemoryGameRepository.GetGame(g.Id)
+		storedGame.NextGameId = "opponentsRematch"
+		_, _ = r.InMemoryGameRepository.UpdateGame(storedGame)
+	}
+	return r.InMemoryGameRepository.UpdateGame(g)
+}
+
+func TestHandlers_Rematch(t *testing.T) {
+	tests := []struct {
+		name               string
+		isFinished         bool
+		nextGameId         string
+		connectionId       string
+		expectedStatusCode int
+		expectedRecipients []string
+	}{
+		{
+			name:               "finished game",
+			isFinished:         true,
+			connectionId:       "playerX",
+			expectedStatusCode: http.StatusOK,
+			expectedRecipients: []string{"playerO"},
+		},
+		{
+			name:               "game in progress",
+			connectionId:       "playerX",
+			expectedStatusCode: http.StatusConflict,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "already rematched",
+			isFinished:         true,
+			nextGameId:         "next",
+			connectionId:       "playerX",
+			expectedStatusCode: http.StatusConflict,
+			expectedRecipients: []string{},
+		},
+		{
+			name:               "not a player",
+			isFinished:         true,
+			connectionId:       "someOtherPlayer",
+			expectedStatusCode: http.StatusForbidden,
+			expectedRecipients: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+			newGame := game.NewGame("playerX", "playerO")
+			if test.isFinished {
+				_ = newGame.Resign("playerO")
+			}
+			newGame.NextGameId = test.nextGameId
+			g, _ := h.Games.CreateGame(*newGame)
+
+			body := fmt.Sprintf(`{"id": "%s"}`, g.Id)
+			response, err := router.Decode(h.Rematch)(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, test.expectedRecipients, messenger.recipients())
+
+			if test.expectedStatusCode == http.StatusOK {
+				rematch := unmarshalGame(t, response)
+				assert.Equal(t, "playerO", rematch.PlayerX)
+				assert.Equal(t, "playerX", rematch.PlayerO)
+				assert.Equal(t, g.Id, rematch.PreviousGameId)
+
+				previousGame, _ := h.Games.GetGame(g.Id)
+				assert.Equal(t, rematch.Id, previousGame.NextGameId)
+			}
+		})
+	}
+}
+
+func TestHandlers_Rematch_ComputerMovesFirst(t *testing.T) {
+	h, _ := newTestHandlers()
+	newGame := game.NewGame("playerX", engine.PlayerId)
+	newGame.ComputerDifficulty = string(engine.Perfect)
+	_ = newGame.Resign("playerX")
+	g, _ := h.Games.CreateGame(*newGame)
+
+	body := fmt.Sprintf(`{"id": "%s"}`, g.Id)
+	response, _ := router.Decode(h.Rematch)(context.Background(), newWebsocketEvent("playerX", body))
+
+	rematch := unmarshalGame(t, response)
+	assert.Equal(t, engine.PlayerId, rematch.PlayerX)
+	assert.Equal(t, 1, len(rematch.Moves))
+	assert.Equal(t, "playerX", rematch.CurrentPlayer())
+}
+
+func TestHandlers_Rematch_ConcurrentRematch(t *testing.T) {
+	h, messenger := newTestHandlers()
+	h.Games = &racedRematchGameRepository{InMemoryGameRepository: db.NewInMemoryGameRepository()}
+	newGame := game.NewGame("playerX", "playerO")
+	_ = newGame.Resign("playerO")
+	g, _ := h.Games.CreateGame(*newGame)
+
+	body := fmt.Sprintf(`{"id": "%s"}`, g.Id)
+	response, err := router.Decode(h.Rematch)(context.Background(), newWebsocketEvent("playerX", body))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Contains(t, response.Body, `"retryable": true`)
+	assert.Equal(t, []string{}, messenger.recipients())
+
+	games, _ := h.Games.ListGamesForPlayer("playerX")
+	assert.Equal(t, 2, len(games))
+	for _, playerGame := range games {
+		if playerGame.Id != g.Id {
+			assert.Equal(t, game.Abandoned, playerGame.Status)
+		}
+	}
+}
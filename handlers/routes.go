@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+)
+
+// Router serves every route of the websocket API, answering requests for actions that have no route with WsFallback
+func (h *Handlers) Router() *router.Router {
+	r := router.New(
+		router.Decode(h.WsFallback),
+		router.Logging,
+		utils.WithEnvelope,
+		router.Recover,
+		router.Errors(utils.ErrorResponse),
+	)
+
+	r.Handle("$connect", h.Connect)
+	r.Handle("$disconnect", h.Disconnect)
+	r.Handle("accept-draw", router.Decode(h.AcceptDraw))
+	r.Handle("analyze", router.Decode(h.Analyze))
+	r.Handle("cancel-match", h.CancelMatch)
+	r.Handle("create-game", router.Decode(h.CreateGame))
+	r.Handle("decline-draw", router.Decode(h.DeclineDraw))
+	r.Handle("export-game", router.Decode(h.ExportGame))
+	r.Handle("find-match", router.Decode(h.FindMatch))
+	r.Handle("get-game", router.Decode(h.GetGame))
+	r.Handle("get-game-history", router.Decode(h.GetGameHistory))
+	r.Handle("import-game", router.Decode(h.ImportGame))
+	r.Handle("join-game", router.Decode(h.JoinGame))
+	r.Handle("list-games", h.ListGames)
+	r.Handle("list-lobby", h.ListLobby)
+	r.Handle("make-move", router.Decode(h.MakeMove))
+	r.Handle("offer-draw", router.Decode(h.OfferDraw))
+	r.Handle("rematch", router.Decode(h.Rematch))
+	r.Handle("resign", router.Decode(h.Resign))
+	r.Handle("send-message", router.Decode(h.SendMessage))
+	r.Handle("watch-game", router.Decode(h.WatchGame))
+
+	return r
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHandlers_Router(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "registered action",
+			body:               `{"action": "get-game", "requestId": "1", "id": "does-not-exist"}`,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"version": 1, "type": "get-game", "requestId": "1", "error": {"code": "ENTITY_NOT_FOUND", "message": "game with ID does-not-exist does not exist"}}`,
+		},
+		{
+			name:               "invalid request",
+			body:               `{"action": "make-move", "move": 4}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"version": 1, "type": "make-move", "error": {"code": "MISSING_FIELD", "message": "id is required"}}`,
+		},
+		{
+			name:               "unknown action",
+			body:               `{"action": "does-not-exist"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"version": 1, "type": "does-not-exist", "error": {"code": "UNKNOWN_ACTION", "message": "Action (does-not-exist) does not correspond to any routes"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, _ := newTestHandlers()
+
+			response, err := h.Router().Route(context.Background(), newWebsocketEvent("playerX", test.body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, test.expectedBody, response.Body)
+		})
+	}
+}
+
+var errUnexpected = errors.New("connection reset by peer")
+
+// failingGameRepository fails every write, and every read too if failReads is set, with an error that has no class
+type failingGameRepository struct {
+	*db.InMemoryGameRepository
+	failReads bool
+}
+
+func (r *failingGameRepository) GetGame(id string) (game.Game, error) {
+	if r.failReads {
+		return game.Game{}, errUnexpected
+	}
+	return r.InMemoryGameRepository.GetGame(id)
+}
+
+func (r *failingGameRepository) CreateGame(game.Game) (game.Game, error) {
+	return game.Game{}, errUnexpected
+}
+
+func (r *failingGameRepository) UpdateGame(game.Game) (game.Game, error) {
+	return game.Game{}, errUnexpected
+}
+
+func (r *failingGameRepository) ListGamesForPlayer(string) ([]game.Game, error) {
+	return nil, errUnexpected
+}
+
+func TestHandlers_Router_UnexpectedErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		failReads bool
+		body      string
+	}{
+		{name: "get-game", failReads: true, body: `{"action": "get-game", "id": "%s"}`},
+		{name: "make-move read", failReads: true, body: `{"action": "make-move", "id": "%s", "move": 4}`},
+		{name: "make-move write", body: `{"action": "make-move", "id": "%s", "move": 4}`},
+		{name: "resign read", failReads: true, body: `{"action": "resign", "id": "%s"}`},
+		{name: "resign write", body: `{"action": "resign", "id": "%s"}`},
+		{name: "offer-draw", body: `{"action": "offer-draw", "id": "%s"}`},
+		{name: "rematch", failReads: true, body: `{"action": "rematch", "id": "%s"}`},
+		{name: "watch-game", failReads: true, body: `{"action": "watch-game", "id": "%s"}`},
+		{name: "analyze", failReads: true, body: `{"action": "analyze", "id": "%s"}`},
+		{name: "export-game", failReads: true, body: `{"action": "export-game", "id": "%s"}`},
+		{name: "get-game-history", failReads: true, body: `{"action": "get-game-history", "id": "%s"}`},
+		{name: "create-game", body: `{"action": "create-game", "playerO": "playerO"}`},
+		{name: "import-game", body: `{"action": "import-game", "notation": "[PlayerX \"playerX\"]\n[PlayerO \"playerO\"]\n\n*"}`},
+		{name: "list-games", body: `{"action": "list-games"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, messenger := newTestHandlers()
+			games := &failingGameRepository{InMemoryGameRepository: db.NewInMemoryGameRepository(), failReads: test.failReads}
+			h.Games = games
+			g, _ := games.InMemoryGameRepository.CreateGame(*game.NewGame("playerX", "playerO"))
+
+			body := test.body
+			if strings.Contains(body, "%s") {
+				body = fmt.Sprintf(body, g.Id)
+			}
+			response, err := h.Router().Route(context.Background(), newWebsocketEvent("playerX", body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+			assert.Contains(t, response.Body, `"code": "INTERNAL_ERROR"`)
+			assert.NotContains(t, response.Body, errUnexpected.Error())
+			assert.Equal(t, []string{}, messenger.recipients())
+
+			storedGame, _ := games.InMemoryGameRepository.GetGame(g.Id)
+			assert.Equal(t, g, storedGame)
+		})
+	}
+}
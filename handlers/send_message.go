@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+func (h *Handlers) SendMessage(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody utils.Request) (events.APIGatewayProxyResponse, error) {
+	messageTo := requestBody.MessageTo
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	_, err = h.Players.GetPlayer(messageTo)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	message := utils.Message{Message: fmt.Sprintf("Hi %s!  From %s", messageTo, playerId)}
+	if err = h.sendToPlayer(websocketEvent, messageTo, utils.ChatMessage, message); err != nil {
+		log.Errorf("An error occurred when sending message from %s to %s - %s", playerId, messageTo, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(utils.Message{Message: fmt.Sprintf("Message sent to %s", messageTo)}), nil
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// SweepClocks runs on a schedule to end the games whose current player has run out of time, as a player who is
+// stalling won't make a request that would notice
+func (h *Handlers) SweepClocks(_ context.Context, _ events.CloudWatchEvent) error {
+	games, err := h.Games.ListGamesOutOfTime(time.Now())
+	if err != nil {
+		log.Errorf("An error occurred while listing games that are out of time - %s", err)
+		return err
+	}
+
+	// There is no websocket request behind a scheduled event, so messages go to the configured endpoint instead
+	websocketEvent := events.APIGatewayWebsocketProxyRequest{}
+	for _, g := range games {
+		if _, err := h.timeOutGame(websocketEvent, g); errors.Is(err, errs.ErrRetryable) {
+			log.Info(err)
+		} else if err != nil {
+			log.Errorf("An error occurred while timing out game with ID %s - %s", g.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// timeOutGame ends the game as a loss for the player whose clock has run out, and lets both players and any
+// spectators know
+func (h *Handlers) timeOutGame(websocketEvent events.APIGatewayWebsocketProxyRequest, g game.Game) (game.Game, error) {
+	if !g.ExpireClock() {
+		return g, nil
+	}
+
+	updatedGame, err := h.Games.UpdateGame(g)
+	if err != nil {
+		return game.Game{}, err
+	}
+	log.Infof("Game %s has finished with status %s", g.Id, updatedGame.Status)
+
+	for _, playerId := range []string{updatedGame.PlayerX, updatedGame.PlayerO} {
+		if err := h.sendToPlayer(websocketEvent, playerId, utils.GameUpdated, updatedGame); err != nil {
+			log.Errorf("An error occurred when sending timed out game %s to %s - %s", g.Id, playerId, err)
+		}
+	}
+	h.sendToSpectators(websocketEvent, g.Id, updatedGame)
+
+	return updatedGame, nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func createTimedGame(h *Handlers, deadline time.Time) game.Game {
+	newGame := game.NewGame("playerX", "playerO")
+	_ = newGame.StartClock(game.TimeControl{Type: game.PerMove, Limit: time.Minute})
+	newGame.Deadline = deadline.UnixNano()
+	g, _ := h.Games.CreateGame(*newGame)
+	return g
+}
+
+func TestHandlers_SweepClocks(t *testing.T) {
+	h, messenger := newTestHandlers()
+	expired := createTimedGame(h, time.Now().Add(-time.Second))
+	running := createTimedGame(h, time.Now().Add(time.Minute))
+	_, _ = h.Spectators.AddSpectator(expired.Id, "someOtherPlayer")
+
+	err := h.SweepClocks(context.Background(), events.CloudWatchEvent{})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"playerX", "playerO", "someOtherPlayer"}, messenger.recipients())
+
+	storedGame, _ := h.Games.GetGame(expired.Id)
+	assert.Equal(t, game.TimedOut, storedGame.Status)
+	assert.Equal(t, game.O, storedGame.Winner)
+	assert.Equal(t, utils.NewEnvelope(utils.GameUpdated, storedGame), messenger.messages[0].message)
+
+	storedGame, _ = h.Games.GetGame(running.Id)
+	assert.Equal(t, game.InProgress, storedGame.Status)
+}
+
+func TestHandlers_SweepClocks_NothingToDo(t *testing.T) {
+	h, messenger := newTestHandlers()
+	_, _ = h.Games.CreateGame(*game.NewGame("playerX", "playerO"))
+
+	err := h.SweepClocks(context.Background(), events.CloudWatchEvent{})
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{}, messenger.recipients())
+}
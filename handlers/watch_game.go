@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+)
+
+type WatchGameRequest struct {
+	Id string `json:"id"`
+}
+
+func (r *WatchGameRequest) Validate() error {
+	return router.Required("id", r.Id)
+}
+
+// WatchGame subscribes the connection to every move made in the game until it disconnects
+func (h *Handlers) WatchGame(_ context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest, requestBody WatchGameRequest) (events.APIGatewayProxyResponse, error) {
+	connectionId := websocketEvent.RequestContext.ConnectionID
+	gameId := requestBody.Id
+
+	playerId, err := h.getPlayerId(websocketEvent)
+	if err != nil {
+		return unknownConnectionResponse(err), nil
+	}
+
+	g, err := h.Games.GetGame(gameId)
+	if err != nil {
+		return utils.ErrorResponse(err), nil
+	}
+
+	if !g.CanView(playerId) {
+		log.Infof("Player with ID %s tried to watch game %s which is private and does not belong to them", playerId, gameId)
+		return utils.ForbiddenResponse(), nil
+	}
+
+	if _, err := h.Spectators.AddSpectator(gameId, connectionId); err != nil {
+		log.Errorf("An error occurred while adding connection with ID %s as a spectator of game %s - %s", connectionId, gameId, err)
+		return utils.InternalServerErrorResponse(), nil
+	}
+
+	return utils.OkResponse(g), nil
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/db"
+	"github.com/Jake-Baum/tic-tac-toe/game"
+	"github.com/Jake-Baum/tic-tac-toe/router"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestHandlers_WatchGame(t *testing.T) {
+	tests := []struct {
+		name                 string
+		connectionId         string
+		gameId               string
+		isPublic             bool
+		expectedStatusCode   int
+		expectedSpectatorIds []string
+	}{
+		{
+			name:                 "public game",
+			connectionId:         "someOtherPlayer",
+			isPublic:             true,
+			expectedStatusCode:   http.StatusOK,
+			expectedSpectatorIds: []string{"someOtherPlayer"},
+		},
+		{
+			name:                 "private game",
+			connectionId:         "someOtherPlayer",
+			expectedStatusCode:   http.StatusForbidden,
+			expectedSpectatorIds: []string{},
+		},
+		{
+			name:                 "player of private game",
+			connectionId:         "playerO",
+			expectedStatusCode:   http.StatusOK,
+			expectedSpectatorIds: []string{"playerO"},
+		},
+		{
+			name:                 "game does not exist",
+			connectionId:         "someOtherPlayer",
+			gameId:               "does-not-exist",
+			expectedStatusCode:   http.StatusNotFound,
+			expectedSpectatorIds: []string{},
+		},
+		{
+			name:                 "unknown connection",
+			connectionId:         "unknownConnection",
+			isPublic:             true,
+			expectedStatusCode:   http.StatusForbidden,
+			expectedSpectatorIds: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, _ := newTestHandlers()
+			newGame := game.NewGame("playerX", "playerO")
+			newGame.IsPublic = test.isPublic
+			g, _ := h.Games.CreateGame(*newGame)
+
+			gameId := g.Id
+			if test.gameId != "" {
+				gameId = test.gameId
+			}
+
+			body := fmt.Sprintf(`{"id": "%s"}`, gameId)
+			response, err := router.Decode(h.WatchGame)(context.Background(), newWebsocketEvent(test.connectionId, body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+
+			spectators, _ := h.Spectators.ListSpectators(g.Id)
+			spectatorIds := []string{}
+			for _, s := range spectators {
+				spectatorIds = append(spectatorIds, s.ConnectionId)
+			}
+			assert.Equal(t, test.expectedSpectatorIds, spectatorIds)
+		})
+	}
+}
+
+func TestHandlers_WatchGame_ReceivesMoves(t *testing.T) {
+	h, messenger := newTestHandlers()
+	connect(h, "spectator", "spectator")
+	newGame := game.NewGame("playerX", "playerO")
+	newGame.IsPublic = true
+	g, _ := h.Games.CreateGame(*newGame)
+
+	body := fmt.Sprintf(`{"id": "%s"}`, g.Id)
+	_, _ = router.Decode(h.WatchGame)(context.Background(), newWebsocketEvent("spectator", body))
+	_, _ = router.Decode(h.WatchGame)(context.Background(), newWebsocketEvent("someOtherPlayer", body))
+
+	body = fmt.Sprintf(`{"id": "%s", "move": 4}`, g.Id)
+	response, _ := router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerX", body))
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{"playerO", "someOtherPlayer", "spectator"}, messenger.recipients())
+	assert.Equal(t, utils.NewEnvelope(utils.GameUpdated, unmarshalGame(t, response)), messenger.messages[2].message)
+}
+
+func TestHandlers_WatchGame_StopsOnDisconnect(t *testing.T) {
+	h, messenger := newTestHandlers()
+	newGame := game.NewGame("playerX", "playerO")
+	newGame.IsPublic = true
+	g, _ := h.Games.CreateGame(*newGame)
+
+	_, _ = router.Decode(h.WatchGame)(context.Background(), newWebsocketEvent("someOtherPlayer", fmt.Sprintf(`{"id": "%s"}`, g.Id)))
+	_, _ = h.Disconnect(context.Background(), newWebsocketEvent("someOtherPlayer", ""))
+
+	body := fmt.Sprintf(`{"id": "%s", "move": 4}`, g.Id)
+	_, _ = router.Decode(h.MakeMove)(context.Background(), newWebsocketEvent("playerX", body))
+
+	assert.Equal(t, []string{"playerO"}, messenger.recipients())
+	spectators, _ := h.Spectators.ListSpectators(g.Id)
+	assert.Equal(t, []db.Spectator{}, spectators)
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type UnknownActionError struct {
+	action string
+}
+
+func (e *UnknownActionError) Error() string {
+	return fmt.Sprintf("Action (%s) does not correspond to any routes", e.action)
+}
+
+func (e *UnknownActionError) Code() string {
+	return "UNKNOWN_ACTION"
+}
+
+func (e *UnknownActionError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+// WsFallback answers requests for actions that have no route
+func (h *Handlers) WsFallback(_ context.Context, _ events.APIGatewayWebsocketProxyRequest, requestBody utils.Request) (events.APIGatewayProxyResponse, error) {
+	return utils.ErrorResponse(&UnknownActionError{action: requestBody.Action}), nil
+}
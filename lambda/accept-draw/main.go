@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/handlers"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+func main() {
+	utils.Initialize()
+	lambda.Start(handlers.NewDynamoHandlers().Router().Handler("accept-draw"))
+}
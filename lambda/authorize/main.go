@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/Jake-Baum/tic-tac-toe/auth"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/lambda"
+	log "github.com/sirupsen/logrus"
+)
+
+func main() {
+	utils.Initialize()
+
+	verifier, err := auth.NewVerifierFromEnvironment()
+	if err != nil {
+		log.Fatalf("An error occurred while loading the token signing keys - %s", err)
+	}
+	lambda.Start(verifier.Authorize)
+}
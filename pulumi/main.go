@@ -7,10 +7,13 @@ import (
 	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/dynamodb"
 	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/iam"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+	"strings"
+	"tic-tac-toe/schedule"
 	"tic-tac-toe/websocket"
 )
 
-func createDynamoTable(ctx *pulumi.Context, name string, attributes dynamodb.TableAttributeArray, isTtlEnabled bool) (*dynamodb.Table, error) {
+func createDynamoTable(ctx *pulumi.Context, name string, attributes dynamodb.TableAttributeArray, isTtlEnabled bool, globalSecondaryIndexes ...dynamodb.TableGlobalSecondaryIndexInput) (*dynamodb.Table, error) {
 	var ttl dynamodb.TableTtlPtrInput
 
 	if isTtlEnabled {
@@ -27,6 +30,8 @@ func createDynamoTable(ctx *pulumi.Context, name string, attributes dynamodb.Tab
 		ReadCapacity:  pulumi.Int(20),
 		WriteCapacity: pulumi.Int(20),
 		Ttl:           ttl,
+
+		GlobalSecondaryIndexes: dynamodb.TableGlobalSecondaryIndexArray(globalSecondaryIndexes),
 	})
 }
 
@@ -37,6 +42,12 @@ func main() {
 			return err
 		}
 
+		// With singleBinary set, every route is served by the router binary rather than a binary of its own
+		lambdaBinary := ""
+		if config.GetBool(ctx, "tic-tac-toe:singleBinary") {
+			lambdaBinary = "router"
+		}
+
 		// An execution lambdaRole to use for the Lambda function
 		lambdaPolicy, err := json.Marshal(map[string]interface{}{
 			"Version": "2012-10-17",
@@ -76,6 +87,58 @@ func main() {
 			return err
 		}
 
+		// Only online players have a Presence, so the presence index lists who is online without scanning the table
+		playerTable, err := createDynamoTable(ctx, "player", dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Id"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Presence"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("ConnectedAt"),
+				Type: pulumi.String("N"),
+			},
+		}, false, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("Presence-ConnectedAt-index"),
+			HashKey:        pulumi.String("Presence"),
+			RangeKey:       pulumi.String("ConnectedAt"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Likewise, only invitations to public games have a Visibility, so only they are listed in the lobby
+		invitationTable, err := createDynamoTable(ctx, "invitation", dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Id"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Visibility"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Ttl"),
+				Type: pulumi.String("N"),
+			},
+		}, true, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("Visibility-Ttl-index"),
+			HashKey:        pulumi.String("Visibility"),
+			RangeKey:       pulumi.String("Ttl"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
+		})
+		if err != nil {
+			return err
+		}
+
 		api, err := apigatewayv2.NewApi(ctx, "websocket-api", &apigatewayv2.ApiArgs{
 			ProtocolType:             pulumi.String("WEBSOCKET"),
 			RouteSelectionExpression: pulumi.String("$request.body.action"),
@@ -84,13 +147,34 @@ func main() {
 			return err
 		}
 
+		// Connections must present a token signed by one of the keys in jwtPublicKeys.  The authorizer always has a
+		// binary of its own, as the router only serves websocket routes
+		authorizer, err := websocket.NewLambdaAuthorizer(ctx, "authorize", websocket.LambdaAuthorizerArgs{
+			LambdaRole: lambdaRole,
+			Api:        api,
+			LambdaEnvironment: pulumi.StringMap{
+				"JWT_AUDIENCE":    pulumi.String(config.Get(ctx, "tic-tac-toe:jwtAudience")),
+				"JWT_ISSUER":      pulumi.String(config.Get(ctx, "tic-tac-toe:jwtIssuer")),
+				"JWT_PUBLIC_KEYS": pulumi.String(config.Require(ctx, "tic-tac-toe:jwtPublicKeys")),
+			},
+			QueryStringParameter: "token",
+		})
+		if err != nil {
+			return err
+		}
+
 		connectLambdaProxy, err := websocket.NewLambdaProxy(ctx, "connect", websocket.LambdaProxyArgs{
 			LambdaRole: lambdaRole,
 			Api:        api,
 			LambdaEnvironment: pulumi.StringMap{
 				"CONNECTION_TABLE_NAME": connectionTable.Name,
+				"INVITATION_TABLE_NAME": invitationTable.Name,
+				"PLAYER_TABLE_NAME":     playerTable.Name,
+				"REGION":                pulumi.String(region.Name),
 			},
-			RouteKey: "$connect",
+			RouteKey:   "$connect",
+			Binary:     lambdaBinary,
+			Authorizer: authorizer,
 		})
 		if err != nil {
 			return err
@@ -100,6 +184,62 @@ func main() {
 			LambdaRole: lambdaRole,
 			Api:        api,
 			RouteKey:   "$default",
+			Binary:     lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		matchQueueTable, err := createDynamoTable(ctx, "match-queue", dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Id"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("GameType"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("QueuedAt"),
+				Type: pulumi.String("N"),
+			},
+		}, true, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("GameType-QueuedAt-index"),
+			HashKey:        pulumi.String("GameType"),
+			RangeKey:       pulumi.String("QueuedAt"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
+		})
+		if err != nil {
+			return err
+		}
+
+		spectatorTable, err := createDynamoTable(ctx, "spectator", dynamodb.TableAttributeArray{
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Id"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("GameId"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("ConnectionId"),
+				Type: pulumi.String("S"),
+			},
+		}, false, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("GameId-index"),
+			HashKey:        pulumi.String("GameId"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
+		}, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("ConnectionId-index"),
+			HashKey:        pulumi.String("ConnectionId"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
 		})
 		if err != nil {
 			return err
@@ -109,9 +249,15 @@ func main() {
 			LambdaRole: lambdaRole,
 			Api:        api,
 			LambdaEnvironment: pulumi.StringMap{
-				"CONNECTION_TABLE_NAME": connectionTable.Name,
+				"CONNECTION_TABLE_NAME":  connectionTable.Name,
+				"INVITATION_TABLE_NAME":  invitationTable.Name,
+				"MATCH_QUEUE_TABLE_NAME": matchQueueTable.Name,
+				"PLAYER_TABLE_NAME":      playerTable.Name,
+				"REGION":                 pulumi.String(region.Name),
+				"SPECTATOR_TABLE_NAME":   spectatorTable.Name,
 			},
 			RouteKey: "$disconnect",
+			Binary:   lambdaBinary,
 		})
 		if err != nil {
 			return err
@@ -122,15 +268,54 @@ func main() {
 				Name: pulumi.String("Id"),
 				Type: pulumi.String("S"),
 			},
-		}, false)
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("PlayerX"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("PlayerO"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Status"),
+				Type: pulumi.String("S"),
+			},
+			&dynamodb.TableAttributeArgs{
+				Name: pulumi.String("Deadline"),
+				Type: pulumi.String("N"),
+			},
+		}, false, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("PlayerX-index"),
+			HashKey:        pulumi.String("PlayerX"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
+		}, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("PlayerO-index"),
+			HashKey:        pulumi.String("PlayerO"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
+		}, &dynamodb.TableGlobalSecondaryIndexArgs{
+			Name:           pulumi.String("Status-Deadline-index"),
+			HashKey:        pulumi.String("Status"),
+			RangeKey:       pulumi.String("Deadline"),
+			ProjectionType: pulumi.String("ALL"),
+			ReadCapacity:   pulumi.Int(20),
+			WriteCapacity:  pulumi.Int(20),
+		})
 		if err != nil {
 			return err
 		}
 
 		gameEnvironment := pulumi.StringMap{
-			"CONNECTION_TABLE_NAME": connectionTable.Name,
-			"GAME_TABLE_NAME":       gameTable.Name,
-			"REGION":                pulumi.String(region.Name),
+			"CONNECTION_TABLE_NAME":  connectionTable.Name,
+			"GAME_TABLE_NAME":        gameTable.Name,
+			"INVITATION_TABLE_NAME":  invitationTable.Name,
+			"MATCH_QUEUE_TABLE_NAME": matchQueueTable.Name,
+			"PLAYER_TABLE_NAME":      playerTable.Name,
+			"REGION":                 pulumi.String(region.Name),
+			"SPECTATOR_TABLE_NAME":   spectatorTable.Name,
 		}
 
 		createGameLambdaProxy, err := websocket.NewLambdaProxy(ctx, "create-game", websocket.LambdaProxyArgs{
@@ -138,6 +323,7 @@ func main() {
 			Api:               api,
 			LambdaEnvironment: gameEnvironment,
 			RouteKey:          "create-game",
+			Binary:            lambdaBinary,
 		})
 		if err != nil {
 			return err
@@ -148,6 +334,7 @@ func main() {
 			Api:               api,
 			LambdaEnvironment: gameEnvironment,
 			RouteKey:          "get-game",
+			Binary:            lambdaBinary,
 		})
 		if err != nil {
 			return err
@@ -158,6 +345,172 @@ func main() {
 			Api:               api,
 			LambdaEnvironment: gameEnvironment,
 			RouteKey:          "make-move",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		getGameHistoryLambdaProxy, err := websocket.NewLambdaProxy(ctx, "get-game-history", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "get-game-history",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		findMatchLambdaProxy, err := websocket.NewLambdaProxy(ctx, "find-match", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "find-match",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		cancelMatchLambdaProxy, err := websocket.NewLambdaProxy(ctx, "cancel-match", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "cancel-match",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		listGamesLambdaProxy, err := websocket.NewLambdaProxy(ctx, "list-games", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "list-games",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		watchGameLambdaProxy, err := websocket.NewLambdaProxy(ctx, "watch-game", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "watch-game",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		resignLambdaProxy, err := websocket.NewLambdaProxy(ctx, "resign", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "resign",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		offerDrawLambdaProxy, err := websocket.NewLambdaProxy(ctx, "offer-draw", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "offer-draw",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		acceptDrawLambdaProxy, err := websocket.NewLambdaProxy(ctx, "accept-draw", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "accept-draw",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		declineDrawLambdaProxy, err := websocket.NewLambdaProxy(ctx, "decline-draw", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "decline-draw",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		rematchLambdaProxy, err := websocket.NewLambdaProxy(ctx, "rematch", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "rematch",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		analyzeLambdaProxy, err := websocket.NewLambdaProxy(ctx, "analyze", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "analyze",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		exportGameLambdaProxy, err := websocket.NewLambdaProxy(ctx, "export-game", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "export-game",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		importGameLambdaProxy, err := websocket.NewLambdaProxy(ctx, "import-game", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "import-game",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		joinGameLambdaProxy, err := websocket.NewLambdaProxy(ctx, "join-game", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "join-game",
+			Binary:            lambdaBinary,
+		})
+		if err != nil {
+			return err
+		}
+
+		listLobbyLambdaProxy, err := websocket.NewLambdaProxy(ctx, "list-lobby", websocket.LambdaProxyArgs{
+			LambdaRole:        lambdaRole,
+			Api:               api,
+			LambdaEnvironment: gameEnvironment,
+			RouteKey:          "list-lobby",
+			Binary:            lambdaBinary,
 		})
 		if err != nil {
 			return err
@@ -172,11 +525,49 @@ func main() {
 				createGameLambdaProxy,
 				getGameLambdaProxy,
 				makeMoveLambdaProxy,
+				getGameHistoryLambdaProxy,
+				findMatchLambdaProxy,
+				cancelMatchLambdaProxy,
+				listGamesLambdaProxy,
+				watchGameLambdaProxy,
+				resignLambdaProxy,
+				offerDrawLambdaProxy,
+				acceptDrawLambdaProxy,
+				declineDrawLambdaProxy,
+				rematchLambdaProxy,
+				analyzeLambdaProxy,
+				exportGameLambdaProxy,
+				importGameLambdaProxy,
+				joinGameLambdaProxy,
+				listLobbyLambdaProxy,
 			},
 		})
 
+		if err != nil {
+			return err
+		}
+
+		_, err = schedule.NewScheduledLambda(ctx, "sweep-clocks", schedule.ScheduledLambdaArgs{
+			LambdaRole: lambdaRole,
+			LambdaEnvironment: pulumi.StringMap{
+				"CONNECTION_TABLE_NAME": connectionTable.Name,
+				"GAME_TABLE_NAME":       gameTable.Name,
+				"PLAYER_TABLE_NAME":     playerTable.Name,
+				"REGION":                pulumi.String(region.Name),
+				"SPECTATOR_TABLE_NAME":  spectatorTable.Name,
+				// The management API lives at the same address as the websocket, but over HTTPS
+				"WEBSOCKET_ENDPOINT": apiStage.WebSocketUrl.ApplyT(func(url string) string {
+					return strings.Replace(url, "wss://", "https://", 1)
+				}).(pulumi.StringOutput),
+			},
+			ScheduleExpression: "rate(1 minute)",
+		})
+		if err != nil {
+			return err
+		}
+
 		ctx.Export("websocketUrl", apiStage.WebSocketUrl)
 
-		return err
+		return nil
 	})
 }
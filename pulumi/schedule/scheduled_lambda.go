@@ -0,0 +1,76 @@
+package schedule
+
+import (
+	"fmt"
+	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/cloudwatch"
+	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/lambda"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+type ScheduledLambda struct {
+	pulumi.ResourceState
+
+	Name           string
+	LambdaFunction *lambda.Function
+	EventRule      *cloudwatch.EventRule
+}
+
+type ScheduledLambdaArgs struct {
+	LambdaRole        *iam.Role
+	LambdaEnvironment pulumi.StringMap
+	// ScheduleExpression is an EventBridge rate or cron expression, e.g. "rate(1 minute)"
+	ScheduleExpression string
+}
+
+func NewScheduledLambda(ctx *pulumi.Context, name string, args ScheduledLambdaArgs, opts ...pulumi.ResourceOption) (*ScheduledLambda, error) {
+	scheduledLambda := &ScheduledLambda{
+		Name: name,
+	}
+	err := ctx.RegisterComponentResource("jakebaum:schedule:ScheduledLambda", name, scheduledLambda, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	parentResourceOption := pulumi.ResourceOption(pulumi.Parent(scheduledLambda))
+
+	scheduledLambda.LambdaFunction, err = lambda.NewFunction(ctx, name, &lambda.FunctionArgs{
+		Runtime: pulumi.String("go1.x"),
+		Handler: pulumi.String("main"),
+		Role:    args.LambdaRole.Arn,
+		Code:    pulumi.NewFileArchive(fmt.Sprintf("../bin/lambda/%s/main.zip", name)),
+		Environment: lambda.FunctionEnvironmentArgs{
+			Variables: args.LambdaEnvironment,
+		},
+	}, parentResourceOption)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledLambda.EventRule, err = cloudwatch.NewEventRule(ctx, name, &cloudwatch.EventRuleArgs{
+		ScheduleExpression: pulumi.String(args.ScheduleExpression),
+	}, parentResourceOption)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = lambda.NewPermission(ctx, name, &lambda.PermissionArgs{
+		Action:    pulumi.String("lambda:InvokeFunction"),
+		Function:  scheduledLambda.LambdaFunction.Name,
+		Principal: pulumi.String("events.amazonaws.com"),
+		SourceArn: scheduledLambda.EventRule.Arn,
+	}, parentResourceOption)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = cloudwatch.NewEventTarget(ctx, name, &cloudwatch.EventTargetArgs{
+		Rule: scheduledLambda.EventRule.Name,
+		Arn:  scheduledLambda.LambdaFunction.Arn,
+	}, parentResourceOption)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheduledLambda, nil
+}
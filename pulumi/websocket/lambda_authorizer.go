@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"fmt"
+	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/apigatewayv2"
+	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/iam"
+	"github.com/pulumi/pulumi-aws/sdk/v5/go/aws/lambda"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+type LambdaAuthorizer struct {
+	pulumi.ResourceState
+
+	Name           string
+	LambdaFunction *lambda.Function
+	Authorizer     *apigatewayv2.Authorizer
+}
+
+type LambdaAuthorizerArgs struct {
+	LambdaRole        *iam.Role
+	Api               *apigatewayv2.Api
+	LambdaEnvironment pulumi.StringMap
+	// QueryStringParameter is the parameter holding the token, which API Gateway rejects the connection without
+	// before invoking the lambda
+	QueryStringParameter string
+	// Binary names the directory under bin/lambda holding the code to deploy, which is the authorizer's own name if
+	// empty
+	Binary string
+}
+
+// NewLambdaAuthorizer deploys a lambda as a request authorizer, for LambdaProxyArgs.Authorizer
+func NewLambdaAuthorizer(ctx *pulumi.Context, name string, args LambdaAuthorizerArgs, opts ...pulumi.ResourceOption) (*LambdaAuthorizer, error) {
+	lambdaAuthorizer := &LambdaAuthorizer{
+		Name: name,
+	}
+	err := ctx.RegisterComponentResource("jakebaum:websocket:LambdaAuthorizer", name, lambdaAuthorizer, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	parentResourceOption := pulumi.ResourceOption(pulumi.Parent(lambdaAuthorizer))
+
+	lambdaAuthorizer.LambdaFunction, err = newLambdaFunction(ctx, name, lambdaFunctionArgs{
+		LambdaRole:        args.LambdaRole,
+		Api:               args.Api,
+		LambdaEnvironment: args.LambdaEnvironment,
+		Binary:            args.Binary,
+	}, parentResourceOption)
+	if err != nil {
+		return nil, err
+	}
+
+	lambdaAuthorizer.Authorizer, err = apigatewayv2.NewAuthorizer(ctx, name, &apigatewayv2.AuthorizerArgs{
+		ApiId:          args.Api.ID(),
+		AuthorizerType: pulumi.String("REQUEST"),
+		AuthorizerUri:  lambdaAuthorizer.LambdaFunction.InvokeArn,
+		IdentitySources: pulumi.StringArray{
+			pulumi.String(fmt.Sprintf("route.request.querystring.%s", args.QueryStringParameter)),
+		},
+		Name: pulumi.String(name),
+	}, parentResourceOption)
+	if err != nil {
+		return nil, err
+	}
+
+	return lambdaAuthorizer, nil
+}
@@ -21,6 +21,11 @@ type LambdaProxyArgs struct {
 	Api               *apigatewayv2.Api
 	LambdaEnvironment pulumi.StringMap
 	RouteKey          string
+	// Binary names the directory under bin/lambda holding the code to deploy, which is the proxy's own name if empty
+	Binary string
+	// Authorizer checks requests to the route before they reach the lambda, if set.  API Gateway only runs
+	// authorizers on the $connect route
+	Authorizer *LambdaAuthorizer
 }
 
 func NewLambdaProxy(ctx *pulumi.Context, name string, args LambdaProxyArgs, opts ...pulumi.ResourceOption) (*LambdaProxy, error) {
@@ -34,26 +39,11 @@ func NewLambdaProxy(ctx *pulumi.Context, name string, args LambdaProxyArgs, opts
 
 	parentResourceOption := pulumi.ResourceOption(pulumi.Parent(lambdaProxyGroup))
 
-	lambdaProxyGroup.LambdaFunction, err = lambda.NewFunction(ctx, name, &lambda.FunctionArgs{
-		Runtime: pulumi.String("go1.x"),
-		Handler: pulumi.String("main"),
-		Role:    args.LambdaRole.Arn,
-		Code:    pulumi.NewFileArchive(fmt.Sprintf("../bin/lambda/%s/main.zip", name)),
-		Environment: lambda.FunctionEnvironmentArgs{
-			Variables: args.LambdaEnvironment,
-		},
-	}, parentResourceOption)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = lambda.NewPermission(ctx, name, &lambda.PermissionArgs{
-		Action:    pulumi.String("lambda:InvokeFunction"),
-		Function:  lambdaProxyGroup.LambdaFunction.Name,
-		Principal: pulumi.String("apigateway.amazonaws.com"),
-		SourceArn: args.Api.ExecutionArn.ApplyT(func(executionArn string) (string, error) {
-			return fmt.Sprintf("%v/*", executionArn), nil
-		}).(pulumi.StringOutput),
+	lambdaProxyGroup.LambdaFunction, err = newLambdaFunction(ctx, name, lambdaFunctionArgs{
+		LambdaRole:        args.LambdaRole,
+		Api:               args.Api,
+		LambdaEnvironment: args.LambdaEnvironment,
+		Binary:            args.Binary,
 	}, parentResourceOption)
 	if err != nil {
 		return nil, err
@@ -72,10 +62,18 @@ func NewLambdaProxy(ctx *pulumi.Context, name string, args LambdaProxyArgs, opts
 		return nil, err
 	}
 
+	authorizationType := pulumi.String("NONE")
+	var authorizerId pulumi.StringPtrInput
+	if args.Authorizer != nil {
+		authorizationType = pulumi.String("CUSTOM")
+		authorizerId = args.Authorizer.Authorizer.ID().ToStringPtrOutput()
+	}
+
 	lambdaProxyGroup.Route, err = apigatewayv2.NewRoute(ctx, name, &apigatewayv2.RouteArgs{
 		ApiId:                            args.Api.ID(),
 		RouteKey:                         pulumi.String(args.RouteKey),
-		AuthorizationType:                pulumi.String("NONE"),
+		AuthorizationType:                authorizationType,
+		AuthorizerId:                     authorizerId,
 		RouteResponseSelectionExpression: pulumi.String("$default"),
 		Target: integration.ID().ApplyT(func(id string) (string, error) {
 			return fmt.Sprintf("integrations/%v", id), nil
@@ -96,3 +94,45 @@ func NewLambdaProxy(ctx *pulumi.Context, name string, args LambdaProxyArgs, opts
 
 	return lambdaProxyGroup, err
 }
+
+type lambdaFunctionArgs struct {
+	LambdaRole        *iam.Role
+	Api               *apigatewayv2.Api
+	LambdaEnvironment pulumi.StringMap
+	Binary            string
+}
+
+// newLambdaFunction deploys the binary as a lambda that API Gateway is allowed to invoke
+func newLambdaFunction(ctx *pulumi.Context, name string, args lambdaFunctionArgs, opts ...pulumi.ResourceOption) (*lambda.Function, error) {
+	binary := args.Binary
+	if binary == "" {
+		binary = name
+	}
+
+	lambdaFunction, err := lambda.NewFunction(ctx, name, &lambda.FunctionArgs{
+		Runtime: pulumi.String("go1.x"),
+		Handler: pulumi.String("main"),
+		Role:    args.LambdaRole.Arn,
+		Code:    pulumi.NewFileArchive(fmt.Sprintf("../bin/lambda/%s/main.zip", binary)),
+		Environment: lambda.FunctionEnvironmentArgs{
+			Variables: args.LambdaEnvironment,
+		},
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = lambda.NewPermission(ctx, name, &lambda.PermissionArgs{
+		Action:    pulumi.String("lambda:InvokeFunction"),
+		Function:  lambdaFunction.Name,
+		Principal: pulumi.String("apigateway.amazonaws.com"),
+		SourceArn: args.Api.ExecutionArn.ApplyT(func(executionArn string) (string, error) {
+			return fmt.Sprintf("%v/*", executionArn), nil
+		}).(pulumi.StringOutput),
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return lambdaFunction, nil
+}
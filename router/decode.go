@@ -0,0 +1,77 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+type MalformedRequestError struct {
+	err error
+}
+
+func (e *MalformedRequestError) Error() string {
+	return fmt.Sprintf("request body is not valid - %s", e.err)
+}
+
+func (e *MalformedRequestError) Code() string {
+	return "MALFORMED_REQUEST"
+}
+
+func (e *MalformedRequestError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+type MissingFieldError struct {
+	field string
+}
+
+func (e *MissingFieldError) Error() string {
+	return fmt.Sprintf("%s is required", e.field)
+}
+
+func (e *MissingFieldError) Code() string {
+	return "MISSING_FIELD"
+}
+
+func (e *MissingFieldError) Is(target error) bool {
+	return target == errs.ErrInvalid
+}
+
+// Validator is implemented by requests that check their own fields once they have been decoded
+type Validator interface {
+	Validate() error
+}
+
+// Required returns a MissingFieldError if a field that has to be given is empty
+func Required(field string, value string) error {
+	if value == "" {
+		return &MissingFieldError{field: field}
+	}
+	return nil
+}
+
+// TypedHandlerFunc handles a request whose body has already been decoded into a T
+type TypedHandlerFunc[T any] func(context.Context, events.APIGatewayWebsocketProxyRequest, T) (events.APIGatewayProxyResponse, error)
+
+// Decode adapts a typed handler into one that can be routed, decoding the body of each request and validating it if
+// T is a Validator. Requests that can't be decoded or aren't valid are rejected without reaching the handler
+func Decode[T any](handler TypedHandlerFunc[T]) utils.HandlerFunc {
+	return func(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+		var request T
+		if err := json.Unmarshal([]byte(websocketEvent.Body), &request); err != nil {
+			return utils.ErrorResponse(&MalformedRequestError{err: err}), nil
+		}
+
+		if validator, ok := interface{}(&request).(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				return utils.ErrorResponse(err), nil
+			}
+		}
+
+		return handler(ctx, websocketEvent, request)
+	}
+}
@@ -0,0 +1,75 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type testRequest struct {
+	Id   string `json:"id"`
+	Move int    `json:"move"`
+}
+
+func (r *testRequest) Validate() error {
+	return Required("id", r.Id)
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "valid request",
+			body:               `{"id": "game", "move": 4}`,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"version": 1, "payload": {"id": "game", "move": 4}}`,
+		},
+		{
+			name:               "malformed body",
+			body:               `{"id": `,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"version": 1, "error": {"code": "MALFORMED_REQUEST", "message": "request body is not valid - unexpected end of JSON input"}}`,
+		},
+		{
+			name:               "field has the wrong type",
+			body:               `{"id": "game", "move": "centre"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"version": 1, "error": {"code": "MALFORMED_REQUEST", "message": "request body is not valid - json: cannot unmarshal string into Go struct field testRequest.move of type int"}}`,
+		},
+		{
+			name:               "missing field",
+			body:               `{"move": 4}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"version": 1, "error": {"code": "MISSING_FIELD", "message": "id is required"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := Decode(func(_ context.Context, _ events.APIGatewayWebsocketProxyRequest, request testRequest) (events.APIGatewayProxyResponse, error) {
+				return utils.OkResponse(request), nil
+			})
+
+			response, err := handler(context.Background(), newEvent("make-move", test.body))
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, test.expectedBody, response.Body)
+		})
+	}
+}
+
+func TestErrors_Is(t *testing.T) {
+	assert.True(t, errors.Is(&MalformedRequestError{err: errors.New("unexpected end of JSON input")}, errs.ErrInvalid))
+	assert.True(t, errors.Is(Required("id", ""), errs.ErrInvalid))
+	assert.Equal(t, nil, Required("id", "game"))
+}
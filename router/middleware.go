@@ -0,0 +1,52 @@
+package router
+
+import (
+	"context"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/sirupsen/logrus"
+	"runtime/debug"
+	"time"
+)
+
+// Logging logs the route and connection of every request, with the status code it was answered with and how long that
+// took
+func Logging(next utils.HandlerFunc) utils.HandlerFunc {
+	return func(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+		start := time.Now()
+		response, err := next(ctx, websocketEvent)
+
+		requestContext := websocketEvent.RequestContext
+		log.Infof("%s %s -> %d in %s", requestContext.ConnectionID, requestContext.RouteKey, response.StatusCode, time.Since(start))
+		return response, err
+	}
+}
+
+// Recover answers a request whose handler panics with an internal server error, so that one bad request can't take
+// down a lambda serving every route
+func Recover(next utils.HandlerFunc) utils.HandlerFunc {
+	return func(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (response events.APIGatewayProxyResponse, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Errorf("Route %s panicked - %v\n%s", websocketEvent.RequestContext.RouteKey, recovered, debug.Stack())
+				response, err = utils.InternalServerErrorResponse(), nil
+			}
+		}()
+
+		return next(ctx, websocketEvent)
+	}
+}
+
+// Errors answers a request whose handler returns an error with the response respond gives for it, rather than letting
+// API Gateway answer with a bare 500
+func Errors(respond func(error) events.APIGatewayProxyResponse) Middleware {
+	return func(next utils.HandlerFunc) utils.HandlerFunc {
+		return func(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+			response, err := next(ctx, websocketEvent)
+			if err != nil {
+				return respond(err), nil
+			}
+			return response, nil
+		}
+	}
+}
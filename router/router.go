@@ -0,0 +1,88 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	"sort"
+)
+
+// DefaultRoute is the route API Gateway sends requests to when their action matches no other route
+const DefaultRoute = "$default"
+
+// Middleware wraps a handler with behaviour shared by every route
+type Middleware func(utils.HandlerFunc) utils.HandlerFunc
+
+// Router picks the handler for a websocket request by its route, so that every route can be served by one lambda, or
+// each route by a lambda of its own
+type Router struct {
+	routes        map[string]utils.HandlerFunc
+	unknownAction utils.HandlerFunc
+	middleware    []Middleware
+}
+
+// New creates a router that answers requests for actions without a route with unknownAction, and runs every request
+// through middleware, the first of which is outermost
+func New(unknownAction utils.HandlerFunc, middleware ...Middleware) *Router {
+	return &Router{
+		routes:        map[string]utils.HandlerFunc{},
+		unknownAction: unknownAction,
+		middleware:    middleware,
+	}
+}
+
+// Handle registers the handler for a route, which is either an action or one of API Gateway's $connect and $disconnect
+func (r *Router) Handle(routeKey string, handler utils.HandlerFunc) {
+	r.routes[routeKey] = handler
+}
+
+func (r *Router) Has(routeKey string) bool {
+	_, ok := r.routes[routeKey]
+	return ok
+}
+
+// RouteKeys lists every route that has been registered, in order
+func (r *Router) RouteKeys() []string {
+	routeKeys := make([]string, 0, len(r.routes))
+	for routeKey := range r.routes {
+		routeKeys = append(routeKeys, routeKey)
+	}
+	sort.Strings(routeKeys)
+	return routeKeys
+}
+
+// Handler returns the handler for a single route with the middleware applied, for a lambda that only serves that
+// route. Routes that have not been registered, such as $default, get the unknown action handler
+func (r *Router) Handler(routeKey string) utils.HandlerFunc {
+	handler, ok := r.routes[routeKey]
+	if !ok {
+		handler = r.unknownAction
+	}
+	return r.wrap(handler)
+}
+
+// Route handles a request for any route. The route API Gateway chose is used if there is one, and otherwise the action
+// in the request's body, the same way the API's RouteSelectionExpression picks it
+func (r *Router) Route(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	routeKey := websocketEvent.RequestContext.RouteKey
+	if !r.Has(routeKey) {
+		var request utils.Request
+		_ = json.Unmarshal([]byte(websocketEvent.Body), &request)
+
+		routeKey = DefaultRoute
+		if r.Has(request.Action) {
+			routeKey = request.Action
+		}
+	}
+
+	websocketEvent.RequestContext.RouteKey = routeKey
+	return r.Handler(routeKey)(ctx, websocketEvent)
+}
+
+func (r *Router) wrap(handler utils.HandlerFunc) utils.HandlerFunc {
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	return handler
+}
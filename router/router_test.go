@@ -0,0 +1,136 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/utils"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+// respondWith returns a handler that answers every request with a message naming the handler
+func respondWith(name string) utils.HandlerFunc {
+	return func(context.Context, events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return utils.OkResponse(utils.Message{Message: name}), nil
+	}
+}
+
+func newTestRouter(middleware ...Middleware) *Router {
+	r := New(respondWith("unknown"), middleware...)
+	r.Handle("$connect", respondWith("connect"))
+	r.Handle("make-move", respondWith("make-move"))
+	return r
+}
+
+func newEvent(routeKey string, body string) events.APIGatewayWebsocketProxyRequest {
+	return events.APIGatewayWebsocketProxyRequest{
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			ConnectionID: "connection",
+			RouteKey:     routeKey,
+		},
+		Body: body,
+	}
+}
+
+func TestRouter_Route(t *testing.T) {
+	tests := []struct {
+		name     string
+		routeKey string
+		body     string
+		expected string
+	}{
+		{
+			name:     "route chosen by API Gateway",
+			routeKey: "make-move",
+			body:     `{"action": "make-move"}`,
+			expected: "make-move",
+		},
+		{
+			name:     "connect",
+			routeKey: "$connect",
+			expected: "connect",
+		},
+		{
+			name:     "action in the body",
+			body:     `{"action": "make-move"}`,
+			expected: "make-move",
+		},
+		{
+			name:     "unknown action",
+			routeKey: DefaultRoute,
+			body:     `{"action": "does-not-exist"}`,
+			expected: "unknown",
+		},
+		{
+			name:     "body is not JSON",
+			body:     `not json`,
+			expected: "unknown",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := newTestRouter().Route(context.Background(), newEvent(test.routeKey, test.body))
+
+			assert.Equal(t, nil, err)
+			assert.JSONEq(t, `{"version": 1, "payload": {"message": "`+test.expected+`"}}`, response.Body)
+		})
+	}
+}
+
+func TestRouter_Handler(t *testing.T) {
+	r := newTestRouter()
+
+	response, _ := r.Handler("make-move")(context.Background(), newEvent("make-move", ""))
+	assert.Contains(t, response.Body, "make-move")
+
+	response, _ = r.Handler(DefaultRoute)(context.Background(), newEvent(DefaultRoute, ""))
+	assert.Contains(t, response.Body, "unknown")
+}
+
+func TestRouter_RouteKeys(t *testing.T) {
+	assert.Equal(t, []string{"$connect", "make-move"}, newTestRouter().RouteKeys())
+}
+
+func TestRouter_Middleware(t *testing.T) {
+	calls := []string{}
+	record := func(name string) Middleware {
+		return func(next utils.HandlerFunc) utils.HandlerFunc {
+			return func(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+				calls = append(calls, name+" "+websocketEvent.RequestContext.RouteKey)
+				return next(ctx, websocketEvent)
+			}
+		}
+	}
+
+	_, _ = newTestRouter(record("outer"), record("inner")).Route(context.Background(), newEvent("", `{"action": "make-move"}`))
+
+	assert.Equal(t, []string{"outer make-move", "inner make-move"}, calls)
+}
+
+func TestRecover(t *testing.T) {
+	handler := Recover(func(context.Context, events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+		panic("something went wrong")
+	})
+
+	response, err := handler(context.Background(), newEvent("make-move", ""))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+}
+
+func TestErrors(t *testing.T) {
+	respond := func(err error) events.APIGatewayProxyResponse {
+		return utils.ConflictResponse(err)
+	}
+	handler := Errors(respond)(func(context.Context, events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("something went wrong")
+	})
+
+	response, err := handler(context.Background(), newEvent("make-move", ""))
+
+	assert.Equal(t, nil, err)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+}
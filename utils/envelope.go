@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// EnvelopeVersion changes whenever the shape of Envelope changes in a way that clients have to handle
+const EnvelopeVersion = 1
+
+// MessageType says what a message carries. Responses take the action of the request they answer, and pushes use one
+// of the types below
+type MessageType string
+
+const (
+	GameStarted  MessageType = "game-started"
+	GameUpdated  MessageType = "game-updated"
+	ChatMessage  MessageType = "chat-message"
+	LobbyUpdated MessageType = "lobby-updated"
+	PlayerJoined MessageType = "player-joined"
+	PlayerLeft   MessageType = "player-left"
+)
+
+// Envelope wraps every message sent over the websocket, whether it answers a request or is pushed
+type Envelope struct {
+	Version   int         `json:"version"`
+	Type      MessageType `json:"type,omitempty"`
+	RequestId string      `json:"requestId,omitempty"`
+	Payload   interface{} `json:"payload,omitempty"`
+	Error     *Error      `json:"error,omitempty"`
+}
+
+type Error struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable,omitempty"`
+}
+
+// Message is the payload of messages that only carry some text
+type Message struct {
+	Message string `json:"message"`
+}
+
+func NewEnvelope(messageType MessageType, payload interface{}) Envelope {
+	return Envelope{
+		Version: EnvelopeVersion,
+		Type:    messageType,
+		Payload: payload,
+	}
+}
+
+// ErrorCode returns the stable code of an error that has one, or defaultCode otherwise
+func ErrorCode(err error, defaultCode string) string {
+	var coder interface{ Code() string }
+	if errors.As(err, &coder) {
+		return coder.Code()
+	}
+	return defaultCode
+}
+
+type HandlerFunc func(context.Context, events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// WithEnvelope fills in the type and request ID of the envelope a handler responds with, from the request it was
+// given, so that clients can match responses up with their requests
+func WithEnvelope(handler HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, websocketEvent events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+		response, err := handler(ctx, websocketEvent)
+		if err != nil || response.Body == "" {
+			return response, err
+		}
+
+		var envelope struct {
+			Version   int             `json:"version"`
+			Type      MessageType     `json:"type,omitempty"`
+			RequestId string          `json:"requestId,omitempty"`
+			Payload   json.RawMessage `json:"payload,omitempty"`
+			Error     *Error          `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal([]byte(response.Body), &envelope); err != nil {
+			return response, nil
+		}
+
+		// $connect and $disconnect have no body, so are named after their route instead
+		var request Request
+		_ = json.Unmarshal([]byte(websocketEvent.Body), &request)
+		envelope.Type = MessageType(request.Action)
+		if envelope.Type == "" {
+			envelope.Type = MessageType(websocketEvent.RequestContext.RouteKey)
+		}
+		envelope.RequestId = request.RequestId
+
+		body, err := json.MarshalIndent(envelope, "", "")
+		if err != nil {
+			return response, nil
+		}
+		response.Body = string(body)
+
+		return response, nil
+	}
+}
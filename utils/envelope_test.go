@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type codedError struct{}
+
+func (e *codedError) Error() string {
+	return "something \"quoted\" went wrong"
+}
+
+func (e *codedError) Code() string {
+	return "SOMETHING_WENT_WRONG"
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "SOMETHING_WENT_WRONG", ErrorCode(&codedError{}, "DEFAULT"))
+	assert.Equal(t, "SOMETHING_WENT_WRONG", ErrorCode(fmt.Errorf("wrapped - %w", &codedError{}), "DEFAULT"))
+	assert.Equal(t, "DEFAULT", ErrorCode(errors.New("plain"), "DEFAULT"))
+}
+
+func TestBadRequestResponse_EscapesMessage(t *testing.T) {
+	response := BadRequestResponse(&codedError{})
+
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	assert.JSONEq(t, `{"version": 1, "error": {"code": "SOMETHING_WENT_WRONG", "message": "something \"quoted\" went wrong"}}`, response.Body)
+}
+
+func TestRetryableConflictResponse(t *testing.T) {
+	response := RetryableConflictResponse(errors.New("try again"))
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.JSONEq(t, `{"version": 1, "error": {"code": "CONFLICT", "message": "try again", "retryable": true}}`, response.Body)
+}
+
+func TestWithEnvelope(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		routeKey     string
+		response     events.APIGatewayProxyResponse
+		expectedBody string
+	}{
+		{
+			name:         "response to a request",
+			body:         `{"action": "get-game", "requestId": "42"}`,
+			routeKey:     "get-game",
+			response:     OkResponse(Message{Message: "hello"}),
+			expectedBody: `{"version": 1, "type": "get-game", "requestId": "42", "payload": {"message": "hello"}}`,
+		},
+		{
+			name:         "error without a request ID",
+			body:         `{"action": "get-game"}`,
+			routeKey:     "get-game",
+			response:     ForbiddenResponse(),
+			expectedBody: `{"version": 1, "type": "get-game", "error": {"code": "FORBIDDEN", "message": "You are not allowed to access this resource"}}`,
+		},
+		{
+			name:         "route without a body",
+			routeKey:     "$connect",
+			response:     OkResponse(Message{Message: "hello"}),
+			expectedBody: `{"version": 1, "type": "$connect", "payload": {"message": "hello"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := WithEnvelope(func(context.Context, events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+				return test.response, nil
+			})
+
+			response, err := handler(context.Background(), events.APIGatewayWebsocketProxyRequest{
+				Body: test.body,
+				RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+					RouteKey: test.routeKey,
+				},
+			})
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, test.response.StatusCode, response.StatusCode)
+			assert.JSONEq(t, test.expectedBody, response.Body)
+		})
+	}
+}
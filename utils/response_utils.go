@@ -2,74 +2,103 @@ package utils
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
 	"github.com/aws/aws-lambda-go/events"
 	log "github.com/sirupsen/logrus"
 	"net/http"
 )
 
-func MessageResponseJson(message string) string {
-	return fmt.Sprintf("{\"message\": \"%s\"}", message)
-}
-
 var defaultHeaders = map[string]string{"Content-Type": "application/json"}
 
-func OkResponse(responseBody interface{}) events.APIGatewayProxyResponse {
-	if responseBodySerialized, err := json.MarshalIndent(responseBody, "", ""); err == nil {
+func newResponse(statusCode int, envelope Envelope) events.APIGatewayProxyResponse {
+	if responseBodySerialized, err := json.MarshalIndent(envelope, "", ""); err == nil {
 		return events.APIGatewayProxyResponse{
-			StatusCode: http.StatusOK,
+			StatusCode: statusCode,
 			Headers:    defaultHeaders,
 			Body:       string(responseBodySerialized),
 		}
 	} else {
-		log.Errorf("An error occurred when serialising response (%s) to JSON - %s", responseBody, err)
+		log.Errorf("An error occurred when serialising response (%v) to JSON - %s", envelope.Payload, err)
 		return InternalServerErrorResponse()
 	}
 }
 
-func BadRequestResponse(message string) events.APIGatewayProxyResponse {
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusBadRequest,
-		Headers:    defaultHeaders,
-		Body:       MessageResponseJson(message),
-	}
+func errorResponse(statusCode int, code string, message string, isRetryable bool) events.APIGatewayProxyResponse {
+	return newResponse(statusCode, Envelope{
+		Version: EnvelopeVersion,
+		Error: &Error{
+			Code:      code,
+			Message:   message,
+			Retryable: isRetryable,
+		},
+	})
+}
+
+func OkResponse(payload interface{}) events.APIGatewayProxyResponse {
+	return newResponse(http.StatusOK, Envelope{
+		Version: EnvelopeVersion,
+		Payload: payload,
+	})
+}
+
+func BadRequestResponse(err error) events.APIGatewayProxyResponse {
+	return errorResponse(http.StatusBadRequest, ErrorCode(err, "BAD_REQUEST"), err.Error(), false)
+}
+
+func UnauthorizedResponse() events.APIGatewayProxyResponse {
+	return errorResponse(http.StatusUnauthorized, "UNAUTHORIZED", "You must provide a valid token to connect", false)
 }
 
 func ForbiddenResponse() events.APIGatewayProxyResponse {
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusForbidden,
-		Headers:    defaultHeaders,
-		Body:       MessageResponseJson("You are not allowed to access this resource"),
-	}
+	return errorResponse(http.StatusForbidden, "FORBIDDEN", "You are not allowed to access this resource", false)
 }
 
 func NotFoundResponse(err error) events.APIGatewayProxyResponse {
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusNotFound,
-		Headers:    defaultHeaders,
-		Body:       MessageResponseJson(err.Error()),
-	}
+	return errorResponse(http.StatusNotFound, ErrorCode(err, "NOT_FOUND"), err.Error(), false)
 }
 
 func MethodNotAllowedResponse() events.APIGatewayProxyResponse {
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusMethodNotAllowed,
-		Headers:    defaultHeaders,
-	}
+	return errorResponse(http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "This method is not allowed", false)
 }
 
-func ConflictResponse(message string) events.APIGatewayProxyResponse {
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusConflict,
-		Headers:    defaultHeaders,
-		Body:       MessageResponseJson(message),
-	}
+func ConflictResponse(err error) events.APIGatewayProxyResponse {
+	return errorResponse(http.StatusConflict, ErrorCode(err, "CONFLICT"), err.Error(), false)
+}
+
+func RetryableConflictResponse(err error) events.APIGatewayProxyResponse {
+	return errorResponse(http.StatusConflict, ErrorCode(err, "CONFLICT"), err.Error(), true)
 }
 
 func InternalServerErrorResponse() events.APIGatewayProxyResponse {
-	return events.APIGatewayProxyResponse{
-		StatusCode: http.StatusInternalServerError,
-		Body:       MessageResponseJson("An unexpected error occurred"),
-		Headers:    defaultHeaders,
+	return errorResponse(http.StatusInternalServerError, "INTERNAL_ERROR", "An unexpected error occurred", false)
+}
+
+// ErrorResponse answers a request that failed with err, choosing the response by the class errors.Is finds for it.
+// Errors without a class are unexpected, so are logged and answered with an internal server error that doesn't give
+// away their message
+func ErrorResponse(err error) events.APIGatewayProxyResponse {
+	switch {
+	case errors.Is(err, errs.ErrInvalid):
+		log.Info(err)
+		return BadRequestResponse(err)
+	case errors.Is(err, errs.ErrUnauthorized):
+		log.Info(err)
+		return UnauthorizedResponse()
+	case errors.Is(err, errs.ErrForbidden):
+		log.Info(err)
+		return ForbiddenResponse()
+	case errors.Is(err, errs.ErrNotFound):
+		log.Info(err)
+		return NotFoundResponse(err)
+	case errors.Is(err, errs.ErrRetryable):
+		log.Info(err)
+		return RetryableConflictResponse(err)
+	case errors.Is(err, errs.ErrConflict):
+		log.Info(err)
+		return ConflictResponse(err)
+	default:
+		log.Errorf("An unexpected error occurred - %s", err)
+		return InternalServerErrorResponse()
 	}
 }
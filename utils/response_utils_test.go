@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Jake-Baum/tic-tac-toe/errs"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+type classifiedError struct {
+	classes []error
+}
+
+func (e *classifiedError) Error() string {
+	return "something went wrong"
+}
+
+func (e *classifiedError) Code() string {
+	return "SOMETHING_WENT_WRONG"
+}
+
+func (e *classifiedError) Is(target error) bool {
+	for _, class := range e.classes {
+		if target == class {
+			return true
+		}
+	}
+	return false
+}
+
+func TestErrorResponse(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "invalid",
+			err:                &classifiedError{classes: []error{errs.ErrInvalid}},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"version": 1, "error": {"code": "SOMETHING_WENT_WRONG", "message": "something went wrong"}}`,
+		},
+		{
+			name:               "unauthorized",
+			err:                &classifiedError{classes: []error{errs.ErrUnauthorized}},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"version": 1, "error": {"code": "UNAUTHORIZED", "message": "You must provide a valid token to connect"}}`,
+		},
+		{
+			name:               "forbidden",
+			err:                &classifiedError{classes: []error{errs.ErrForbidden}},
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       `{"version": 1, "error": {"code": "FORBIDDEN", "message": "You are not allowed to access this resource"}}`,
+		},
+		{
+			name:               "not found",
+			err:                &classifiedError{classes: []error{errs.ErrNotFound}},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"version": 1, "error": {"code": "SOMETHING_WENT_WRONG", "message": "something went wrong"}}`,
+		},
+		{
+			name:               "conflict",
+			err:                &classifiedError{classes: []error{errs.ErrConflict}},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       `{"version": 1, "error": {"code": "SOMETHING_WENT_WRONG", "message": "something went wrong"}}`,
+		},
+		{
+			name:               "retryable conflict",
+			err:                &classifiedError{classes: []error{errs.ErrConflict, errs.ErrRetryable}},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       `{"version": 1, "error": {"code": "SOMETHING_WENT_WRONG", "message": "something went wrong", "retryable": true}}`,
+		},
+		{
+			name:               "sentinel",
+			err:                errs.ErrNotFound,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"version": 1, "error": {"code": "NOT_FOUND", "message": "not found"}}`,
+		},
+		{
+			name:               "wrapped",
+			err:                fmt.Errorf("while retrieving game - %w", &classifiedError{classes: []error{errs.ErrInvalid}}),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"version": 1, "error": {"code": "SOMETHING_WENT_WRONG", "message": "while retrieving game - something went wrong"}}`,
+		},
+		{
+			name:               "unclassified",
+			err:                &codedError{},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"version": 1, "error": {"code": "INTERNAL_ERROR", "message": "An unexpected error occurred"}}`,
+		},
+		{
+			name:               "plain",
+			err:                errors.New("connection reset by peer"),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"version": 1, "error": {"code": "INTERNAL_ERROR", "message": "An unexpected error occurred"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := ErrorResponse(test.err)
+
+			assert.Equal(t, test.expectedStatusCode, response.StatusCode)
+			assert.JSONEq(t, test.expectedBody, response.Body)
+		})
+	}
+}
@@ -13,15 +13,31 @@ import (
 
 type Request struct {
 	Action    string `json:"action"`
+	RequestId string `json:"requestId"`
 	MessageTo string `json:"messageTo"`
 }
 
 var region = os.Getenv("REGION")
 
+// websocketEndpoint is only needed by lambdas that aren't invoked through the websocket API, such as scheduled ones,
+// as the others can work out the endpoint from the request
+var websocketEndpoint = os.Getenv("WEBSOCKET_ENDPOINT")
+
+type Messenger interface {
+	SendMessage(websocketEvent events.APIGatewayWebsocketProxyRequest, messageTo string, message interface{}) error
+}
+
+type ApiGatewayMessenger struct{}
+
 func newApiGatewaySession(websocketEvent events.APIGatewayWebsocketProxyRequest) (*apigatewaymanagementapi.ApiGatewayManagementApi, error) {
+	endpoint := websocketEndpoint
+	if websocketEvent.RequestContext.DomainName != "" {
+		endpoint = fmt.Sprintf("https://%s/%s", websocketEvent.RequestContext.DomainName, websocketEvent.RequestContext.Stage)
+	}
+
 	sess, err := session.NewSession(&aws.Config{
 		Region:   aws.String(region),
-		Endpoint: aws.String(fmt.Sprintf("https://%s/%s", websocketEvent.RequestContext.DomainName, websocketEvent.RequestContext.Stage)),
+		Endpoint: aws.String(endpoint),
 	})
 
 	if err != nil {
@@ -31,7 +47,7 @@ func newApiGatewaySession(websocketEvent events.APIGatewayWebsocketProxyRequest)
 	return apigatewaymanagementapi.New(sess), nil
 }
 
-func SendMessage(websocketEvent events.APIGatewayWebsocketProxyRequest, messageTo string, message interface{}) error {
+func (m *ApiGatewayMessenger) SendMessage(websocketEvent events.APIGatewayWebsocketProxyRequest, messageTo string, message interface{}) error {
 	responseBodySerialized, err := json.MarshalIndent(message, "", "")
 	if err != nil {
 		return err